@@ -0,0 +1,107 @@
+package bulkapi
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// csvSplitter splits CSV data into chunks of whole records. Each chunk starts with the header
+// of the data. Records can span multiple lines if a field is quoted.
+type csvSplitter struct {
+	r       *bufio.Reader
+	header  []byte
+	pending []byte // record read but not written to a chunk
+	eof     bool
+}
+
+// newCSVSplitter returns a splitter for the CSV data. The header is read from the data.
+func newCSVSplitter(data io.Reader) (*csvSplitter, error) {
+	s := &csvSplitter{r: bufio.NewReader(data)}
+	header, err := s.readRecord()
+	if err != nil {
+		return nil, err
+	}
+	if len(header) == 0 {
+		return nil, errors.New("csv header is required")
+	}
+	s.header = header
+	return s, s.readPending()
+}
+
+// more returns true if there are records that have not been written to a chunk.
+func (s *csvSplitter) more() bool {
+	return len(s.pending) > 0
+}
+
+// writeChunk writes the header and the next records to w. The chunk is at most maxSize
+// bytes, and contains at least one record.
+func (s *csvSplitter) writeChunk(w io.Writer, maxSize int) error {
+	if len(s.header)+len(s.pending) > maxSize {
+		return fmt.Errorf("csv record exceeds the maximum upload size of %d bytes", maxSize)
+	}
+	if _, err := w.Write(s.header); err != nil {
+		return err
+	}
+	size := len(s.header)
+	for s.more() && size+len(s.pending) <= maxSize {
+		if _, err := w.Write(s.pending); err != nil {
+			return err
+		}
+		size += len(s.pending)
+		if err := s.readPending(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPending reads the next non-empty record into pending.
+func (s *csvSplitter) readPending() error {
+	for {
+		record, err := s.readRecord()
+		if err != nil {
+			return err
+		}
+		s.pending = record
+		if len(record) == 0 || len(bytes.TrimSpace(record)) > 0 {
+			return nil
+		}
+	}
+}
+
+// readRecord returns the next record, including its line ending. The line ending of the
+// header is added to the last record if it is missing. Returns an empty record at the end
+// of the data.
+func (s *csvSplitter) readRecord() ([]byte, error) {
+	var record []byte
+	inQuotes := false
+	for !s.eof {
+		line, err := s.r.ReadBytes('\n')
+		if err == io.EOF {
+			s.eof = true
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %v", err)
+		}
+		record = append(record, line...)
+		// an escaped quote ("") toggles twice
+		if bytes.Count(line, []byte{'"'})%2 == 1 {
+			inQuotes = !inQuotes
+		}
+		if !inQuotes && len(record) > 0 {
+			break
+		}
+	}
+	if inQuotes {
+		return nil, errors.New("failed to read csv: unterminated quoted field")
+	}
+	if len(record) > 0 && record[len(record)-1] != '\n' {
+		if bytes.HasSuffix(s.header, []byte("\r\n")) {
+			record = append(record, '\r')
+		}
+		record = append(record, '\n')
+	}
+	return record, nil
+}
@@ -0,0 +1,62 @@
+package bulkapi
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVSplitter(t *testing.T) {
+	tests := []struct {
+		data       string
+		maxSize    int
+		want       []string
+		errSnippet string
+	}{
+		{"", 100, nil, "csv header is required"},
+		{"Name\n", 100, []string{"Name\n"}, ""},
+		{"Name\nA\nB\n", 100, []string{"Name\nA\nB\n"}, ""},
+		{"Name\nA\nB", 100, []string{"Name\nA\nB\n"}, ""},
+		{"Name\r\nA\r\nB", 100, []string{"Name\r\nA\r\nB\r\n"}, ""},
+		{"Name\nA\n\nB\n\n", 100, []string{"Name\nA\nB\n"}, ""},
+		{"Name\nAA\nBB\nCC\n", 11, []string{"Name\nAA\nBB\n", "Name\nCC\n"}, ""},
+		{"Name\nAA\nBB\nCC\n", 8, []string{"Name\nAA\n", "Name\nBB\n", "Name\nCC\n"}, ""},
+		{"Name,Desc\nA,\"line 1\nline \"\"2\"\"\"\nB,b\n", 35,
+			[]string{"Name,Desc\nA,\"line 1\nline \"\"2\"\"\"\n", "Name,Desc\nB,b\n"}, ""},
+		{"Name\nAAAAAAAA\n", 8, nil, "csv record exceeds the maximum upload size of 8 bytes"},
+		{"Name\n\"A\n", 100, nil, "unterminated quoted field"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %q", test.data)
+		got, err := splitCSV(test.data, test.maxSize)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		assert.Nil(t, err, assertMsg)
+		assert.Equal(t, test.want, got, assertMsg)
+	}
+}
+
+// splitCSV returns the chunks of the CSV data.
+func splitCSV(data string, maxSize int) ([]string, error) {
+	s, err := newCSVSplitter(strings.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var chunks []string
+	for first := true; first || s.more(); first = false {
+		var buf bytes.Buffer
+		if err := s.writeChunk(&buf, maxSize); err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, buf.String())
+	}
+	return chunks, nil
+}
@@ -0,0 +1,365 @@
+package bulkapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+const (
+	ingestPath = "/services/data/%s/jobs/ingest/"
+	// uploadLimit is the maximum size of the base64 encoded data uploaded to a job.
+	uploadLimit = 150 * 1024 * 1024
+	// maxUploadSize is the maximum size of the data uploaded to a job, so that the data is
+	// within the upload limit once base64 encoded.
+	maxUploadSize = uploadLimit / 4 * 3
+	// defaultPollInterval is the default time between polls of a job.
+	defaultPollInterval = 5 * time.Second
+)
+
+// errUploadFinished closes the data of an upload once the request is sent.
+var errUploadFinished = errors.New("upload finished before the data was read")
+
+// ingest operations
+const (
+	OperationInsert     = "insert"
+	OperationUpdate     = "update"
+	OperationUpsert     = "upsert"
+	OperationDelete     = "delete"
+	OperationHardDelete = "hardDelete"
+)
+
+// job states
+const (
+	JobStateOpen           = "Open"
+	JobStateUploadComplete = "UploadComplete"
+	JobStateInProgress     = "InProgress"
+	JobStateJobComplete    = "JobComplete"
+	JobStateFailed         = "Failed"
+	JobStateAborted        = "Aborted"
+)
+
+// ingest job result types
+const (
+	ResultTypeSuccessful  = "successfulResults"
+	ResultTypeFailed      = "failedResults"
+	ResultTypeUnprocessed = "unprocessedrecords"
+)
+
+// CreateJobInput stores the input for creating an ingest job. The external id field is
+// required for upserts. The line ending (LF or CRLF) and column delimiter (e.g. COMMA, TAB)
+// of the CSV data default to LF and COMMA.
+type CreateJobInput struct {
+	Object              string
+	Operation           string
+	ExternalIDFieldName string
+	LineEnding          string
+	ColumnDelimiter     string
+}
+
+// CreateJobOutput stores the output after creating an ingest job.
+type CreateJobOutput struct {
+	Job *JobInfo
+}
+
+// CreateJob creates an ingest job using the Salesforce Bulk API 2.0. The job is open until
+// the data is uploaded and the job is closed.
+func (c *Client) CreateJob(input *CreateJobInput) (*CreateJobOutput, error) {
+	// validate parameters
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(&JobInfo{
+		Object:              input.Object,
+		Operation:           input.Operation,
+		ExternalIDFieldName: input.ExternalIDFieldName,
+		ContentType:         "CSV",
+		LineEnding:          input.LineEnding,
+		ColumnDelimiter:     input.ColumnDelimiter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal job: %v", err)
+	}
+	var job JobInfo
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPost,
+		APIPath: c.ingestPath(),
+		Body:    buf,
+	}, request.JSONResult, &job, http.StatusOK)
+	return &CreateJobOutput{&job}, req.Send()
+}
+
+func (input *CreateJobInput) validate() error {
+	if !restapi.IsValidFieldName(input.Object) {
+		return errors.New("invalid object name")
+	}
+	switch input.Operation {
+	case OperationInsert, OperationUpdate, OperationDelete, OperationHardDelete:
+		if input.ExternalIDFieldName != "" {
+			return errors.New("external id field is only valid for upserts")
+		}
+	case OperationUpsert:
+		if !restapi.IsValidFieldName(input.ExternalIDFieldName) {
+			return errors.New("invalid external id field")
+		}
+	default:
+		return fmt.Errorf("invalid operation %q", input.Operation)
+	}
+	return nil
+}
+
+// UploadJobDataInput stores the input for uploading the CSV data of an ingest job. The data
+// must fit the 150 MB upload limit, see Ingest to split larger data into multiple jobs.
+type UploadJobDataInput struct {
+	JobID string
+	Data  io.Reader
+}
+
+// UploadJobDataOutput stores the output after uploading the CSV data of an ingest job.
+type UploadJobDataOutput struct {
+}
+
+// UploadJobData uploads the CSV data of the open ingest job using the Salesforce Bulk API
+// 2.0. The data can only be uploaded once per job. The data is streamed instead of read into
+// memory, so the request is not retried if the session expired.
+func (c *Client) UploadJobData(input *UploadJobDataInput) (*UploadJobDataOutput, error) {
+	// validate parameters
+	if input.JobID == "" {
+		return nil, errors.New("job id is required")
+	}
+	if input.Data == nil {
+		return nil, errors.New("data is required")
+	}
+
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPut,
+		APIPath: c.ingestPath(input.JobID, "batches"),
+		Header:  http.Header{"Content-Type": {"text/csv"}},
+		Body:    input.Data,
+	}, request.JSONResult, nil, http.StatusCreated)
+	return &UploadJobDataOutput{}, req.Send()
+}
+
+// CloseJobInput stores the input for closing an ingest job.
+type CloseJobInput struct {
+	JobID string
+}
+
+// CloseJobOutput stores the output after closing an ingest job.
+type CloseJobOutput struct {
+	Job *JobInfo
+}
+
+// CloseJob marks the upload of the ingest job as complete using the Salesforce Bulk API 2.0.
+// The job is queued for processing.
+func (c *Client) CloseJob(input *CloseJobInput) (*CloseJobOutput, error) {
+	job, err := c.setJobState(c.ingestPath(), input.JobID, JobStateUploadComplete)
+	return &CloseJobOutput{job}, err
+}
+
+// AbortJobInput stores the input for aborting an ingest job.
+type AbortJobInput struct {
+	JobID string
+}
+
+// AbortJobOutput stores the output after aborting an ingest job.
+type AbortJobOutput struct {
+	Job *JobInfo
+}
+
+// AbortJob aborts the ingest job using the Salesforce Bulk API 2.0. The records already
+// processed are not rolled back.
+func (c *Client) AbortJob(input *AbortJobInput) (*AbortJobOutput, error) {
+	job, err := c.setJobState(c.ingestPath(), input.JobID, JobStateAborted)
+	return &AbortJobOutput{job}, err
+}
+
+// GetJobInput stores the input for retrieving an ingest job.
+type GetJobInput struct {
+	JobID string
+}
+
+// GetJobOutput stores the output after retrieving an ingest job.
+type GetJobOutput struct {
+	Job *JobInfo
+}
+
+// GetJob retrieves the state and progress of the ingest job from the Salesforce Bulk API 2.0.
+func (c *Client) GetJob(input *GetJobInput) (*GetJobOutput, error) {
+	job, err := c.getJob(c.ingestPath(), input.JobID)
+	return &GetJobOutput{job}, err
+}
+
+// DeleteJobInput stores the input for deleting an ingest job.
+type DeleteJobInput struct {
+	JobID string
+}
+
+// DeleteJobOutput stores the output after deleting an ingest job.
+type DeleteJobOutput struct {
+}
+
+// DeleteJob deletes the ingest job and its data using the Salesforce Bulk API 2.0. The job
+// must be complete, failed or aborted.
+func (c *Client) DeleteJob(input *DeleteJobInput) (*DeleteJobOutput, error) {
+	return &DeleteJobOutput{}, c.deleteJob(c.ingestPath(), input.JobID)
+}
+
+// WaitForJobInput stores the input for waiting for an ingest job to be processed. The job is
+// polled every poll interval, which defaults to 5 seconds.
+type WaitForJobInput struct {
+	JobID        string
+	PollInterval time.Duration
+}
+
+// WaitForJobOutput stores the output after an ingest job was processed.
+type WaitForJobOutput struct {
+	Job *JobInfo
+}
+
+// WaitForJob polls the ingest job until it is complete, failed or aborted. Waiting stops if
+// the context is done.
+func (c *Client) WaitForJob(ctx context.Context, input *WaitForJobInput) (*WaitForJobOutput, error) {
+	job, err := c.waitForJob(ctx, c.ingestPath(), input.JobID, input.PollInterval)
+	if err != nil {
+		return nil, err
+	}
+	return &WaitForJobOutput{job}, nil
+}
+
+// GetJobResultsInput stores the input for downloading the results of an ingest job. The
+// result type is successfulResults, failedResults or unprocessedrecords.
+type GetJobResultsInput struct {
+	JobID      string
+	ResultType string
+}
+
+// GetJobResultsOutput stores the output after downloading the results of an ingest job. The
+// body is CSV data, and the caller must close the body.
+type GetJobResultsOutput struct {
+	Body io.ReadCloser
+}
+
+// GetJobResults downloads the successful, failed or unprocessed records of the ingest job
+// from the Salesforce Bulk API 2.0. The results are streamed instead of read into memory.
+func (c *Client) GetJobResults(input *GetJobResultsInput) (*GetJobResultsOutput, error) {
+	// validate parameters
+	if input.JobID == "" {
+		return nil, errors.New("job id is required")
+	}
+	switch input.ResultType {
+	case ResultTypeSuccessful, ResultTypeFailed, ResultTypeUnprocessed:
+	default:
+		return nil, fmt.Errorf("invalid result type %q", input.ResultType)
+	}
+
+	var body io.ReadCloser
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodGet,
+		APIPath: c.ingestPath(input.JobID, input.ResultType),
+		Header:  http.Header{"Accept": {"text/csv"}},
+	}, request.StreamResult, &body, http.StatusOK)
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+	return &GetJobResultsOutput{body}, nil
+}
+
+// IngestInput stores the input for ingesting CSV data. The first line of the data is the
+// header.
+type IngestInput struct {
+	Job  *CreateJobInput
+	Data io.Reader
+}
+
+// IngestOutput stores the output after ingesting CSV data. There is a job for each chunk of
+// the data.
+type IngestOutput struct {
+	Jobs []*JobInfo
+}
+
+// Ingest uploads the CSV data using ingest jobs and closes the jobs. Data larger than the
+// 150 MB upload limit is split into chunks of whole records, and a job is created for each
+// chunk with the header repeated. Use WaitForJob to wait for the jobs to be processed. If an
+// upload fails, the job is aborted and the jobs already closed are returned with the error.
+// An error is returned without creating a job if the data has no records.
+func (c *Client) Ingest(input *IngestInput) (*IngestOutput, error) {
+	return c.ingest(input, maxUploadSize)
+}
+
+func (c *Client) ingest(input *IngestInput, maxSize int) (*IngestOutput, error) {
+	// validate parameters
+	if input.Job == nil {
+		return nil, errors.New("job is required")
+	}
+	if err := input.Job.validate(); err != nil {
+		return nil, err
+	}
+	if input.Data == nil {
+		return nil, errors.New("data is required")
+	}
+	splitter, err := newCSVSplitter(input.Data)
+	if err != nil {
+		return nil, err
+	}
+	if !splitter.more() {
+		return nil, errors.New("csv data has no records")
+	}
+
+	out := &IngestOutput{}
+	for splitter.more() {
+		created, err := c.CreateJob(input.Job)
+		if err != nil {
+			return out, err
+		}
+
+		// stream chunk to upload
+		pr, pw := io.Pipe()
+		chunkErr := make(chan error, 1)
+		go func() {
+			err := splitter.writeChunk(pw, maxSize)
+			pw.CloseWithError(err)
+			chunkErr <- err
+		}()
+		_, err = c.UploadJobData(&UploadJobDataInput{JobID: created.Job.ID, Data: pr})
+		// unblock the chunk writer if the upload stopped reading
+		_ = pr.CloseWithError(errUploadFinished)
+		// the writer fails with a closed pipe if the upload was rejected, so only a read or
+		// parse error of the data replaces the upload error
+		if werr := <-chunkErr; werr != nil && (err == nil || !isClosedPipe(werr)) {
+			err = werr
+		}
+		if err != nil {
+			_, _ = c.AbortJob(&AbortJobInput{created.Job.ID})
+			return out, err
+		}
+
+		closed, err := c.CloseJob(&CloseJobInput{created.Job.ID})
+		if err != nil {
+			return out, err
+		}
+		out.Jobs = append(out.Jobs, closed.Job)
+	}
+	return out, nil
+}
+
+// isClosedPipe returns true if the error is from writing to a closed upload pipe.
+func isClosedPipe(err error) bool {
+	return err == errUploadFinished || err == io.ErrClosedPipe
+}
+
+// ingestPath returns the api path for the ingest job resource joined with the elements.
+func (c *Client) ingestPath(elem ...string) string {
+	return path.Join(append([]string{fmt.Sprintf(ingestPath, c.sess.APIVersion)}, elem...)...)
+}
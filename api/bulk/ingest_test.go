@@ -0,0 +1,557 @@
+package bulkapi
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/Laugusti/go-sforce/sforce/credentials"
+	"github.com/Laugusti/go-sforce/sforce/session"
+	"github.com/Laugusti/go-sforce/sforce/sforceerr"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	accessToken = "MOCK_TOKEN"
+	apiVersion  = "mock"
+)
+
+var (
+	// api error
+	genericErr = sforceerr.APIError{Message: "Generic API error", ErrorCode: "GENERIC_ERROR"}
+
+	// request validators
+	jsonContentTypeValidator = &testserver.HeaderValidator{Key: "Content-Type", Value: "application/json"}
+	csvContentTypeValidator  = &testserver.HeaderValidator{Key: "Content-Type", Value: "text/csv"}
+	authTokenValidator       = &testserver.HeaderValidator{Key: "Authorization", Value: "Bearer " + accessToken}
+	emptyQueryValidator      = &testserver.QueryValidator{Query: url.Values{}}
+	emptyBodyValidator       = &testserver.JSONBodyValidator{Body: nil}
+	getMethodValidator       = &testserver.MethodValidator{Method: http.MethodGet}
+	postMethodValidator      = &testserver.MethodValidator{Method: http.MethodPost}
+	putMethodValidator       = &testserver.MethodValidator{Method: http.MethodPut}
+	patchMethodValidator     = &testserver.MethodValidator{Method: http.MethodPatch}
+	deleteMethodValidator    = &testserver.MethodValidator{Method: http.MethodDelete}
+)
+
+// requestRecorder records the method, path (with the query, if any) and body of each
+// request. The body is recorded as far as it could be read, since aborted uploads are cut
+// short.
+type requestRecorder struct {
+	requests []string
+}
+
+// Validate implements the RequestValidator interface.
+func (r *requestRecorder) Validate(req *http.Request) error {
+	b, _ := ioutil.ReadAll(req.Body)
+	path := req.URL.Path
+	if req.URL.RawQuery != "" {
+		path += "?" + req.URL.RawQuery
+	}
+	r.requests = append(r.requests, strings.TrimSpace(req.Method+" "+path+" "+string(b)))
+	return nil
+}
+
+// textBodyValidator validates the request body as text.
+type textBodyValidator struct {
+	body string
+}
+
+// Validate implements the RequestValidator interface.
+func (v *textBodyValidator) Validate(r *http.Request) error {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("textBodyValidator failed: could not read request body: %v", err)
+	}
+	if string(b) != v.body {
+		return fmt.Errorf("textBodyValidator failed: want %q, got %q", v.body, b)
+	}
+	return nil
+}
+
+func createClientAndServer(t *testing.T) (*Client, *testserver.Server) {
+	// start server
+	s := testserver.New(t)
+
+	// create session and login
+	s.HandlerFunc = testserver.StaticJSONHandlerFunc(t, http.StatusOK,
+		session.RequestToken{
+			AccessToken: accessToken,
+			InstanceURL: s.URL(),
+		})
+	sess := session.Must(session.New(
+		s.URL(),
+		apiVersion,
+		credentials.New("user", "pass", "cid", "csecret"),
+	))
+	if err := sess.Login(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, s.RequestCount, "expected single request (login)")
+	s.RequestCount = 0 // reset counter
+
+	// create client
+	client := &Client{sess}
+
+	return client, s
+}
+
+func TestCreateJob(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		input        *CreateJobInput
+		wantBody     map[string]interface{}
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{&CreateJobInput{Object: "", Operation: OperationInsert}, nil, 0, 0, "invalid object name"},
+		{&CreateJobInput{Object: "Account", Operation: "merge"}, nil, 0, 0, `invalid operation "merge"`},
+		{&CreateJobInput{Object: "Account", Operation: OperationUpsert}, nil, 0, 0, "invalid external id field"},
+		{&CreateJobInput{Object: "Account", Operation: OperationInsert, ExternalIDFieldName: "Ext__c"},
+			nil, 0, 0, "external id field is only valid for upserts"},
+		{&CreateJobInput{Object: "Account", Operation: OperationInsert},
+			map[string]interface{}{"object": "Account", "operation": "insert", "contentType": "CSV"},
+			200, 1, ""},
+		{&CreateJobInput{Object: "Account", Operation: OperationUpsert, ExternalIDFieldName: "Ext__c",
+			LineEnding: "CRLF", ColumnDelimiter: "TAB"},
+			map[string]interface{}{"object": "Account", "operation": "upsert", "contentType": "CSV",
+				"externalIdFieldName": "Ext__c", "lineEnding": "CRLF", "columnDelimiter": "TAB"},
+			200, 1, ""},
+		{&CreateJobInput{Object: "Account", Operation: OperationHardDelete},
+			map[string]interface{}{"object": "Account", "operation": "hardDelete", "contentType": "CSV"},
+			400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/jobs/ingest", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.JSONBodyValidator{Body: test.wantBody},
+			&testserver.PathValidator{Path: path}, postMethodValidator}
+
+		want := &JobInfo{ID: "750", Object: "Account", State: JobStateOpen}
+		requestFunc := func() (interface{}, error) {
+			return client.CreateJob(test.input)
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*CreateJobOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, want, out.Job, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: want}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestUploadJobData(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	data := "Name\nAcme\n"
+	tests := []struct {
+		jobID        string
+		data         *strings.Reader
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", strings.NewReader(data), 0, 0, "job id is required"},
+		{"750", nil, 0, 0, "data is required"},
+		{"750", strings.NewReader(data), 201, 1, ""},
+		{"750", strings.NewReader(data), 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/jobs/ingest/%s/batches", apiVersion, test.jobID)
+		validators := []testserver.RequestValidator{authTokenValidator, csvContentTypeValidator,
+			emptyQueryValidator, &textBodyValidator{data},
+			&testserver.PathValidator{Path: path}, putMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			input := &UploadJobDataInput{JobID: test.jobID}
+			if test.data != nil {
+				input.Data = test.data
+			}
+			return client.UploadJobData(input)
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, nil,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestSetJobState(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		jobID        string
+		state        string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", JobStateUploadComplete, 0, 0, "job id is required"},
+		{"", JobStateAborted, 0, 0, "job id is required"},
+		{"750", JobStateUploadComplete, 200, 1, ""},
+		{"750", JobStateAborted, 200, 1, ""},
+		{"750", JobStateUploadComplete, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/jobs/ingest/%s", apiVersion, test.jobID)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.JSONBodyValidator{Body: map[string]string{"state": test.state}},
+			&testserver.PathValidator{Path: path}, patchMethodValidator}
+
+		want := &JobInfo{ID: "750", State: test.state}
+		requestFunc := func() (interface{}, error) {
+			if test.state == JobStateAborted {
+				out, err := client.AbortJob(&AbortJobInput{test.jobID})
+				if err != nil {
+					return nil, err
+				}
+				return out.Job, nil
+			}
+			out, err := client.CloseJob(&CloseJobInput{test.jobID})
+			if err != nil {
+				return nil, err
+			}
+			return out.Job, nil
+		}
+		successFunc := func(res interface{}) {
+			assert.Equal(t, want, res, assertMsg)
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: want}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		jobID        string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", 0, 0, "job id is required"},
+		{"750", 200, 1, ""},
+		{"750", 404, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/jobs/ingest/%s", apiVersion, test.jobID)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		want := &JobInfo{ID: "750", State: JobStateInProgress, APIVersion: 50,
+			NumberRecordsProcessed: 10, NumberRecordsFailed: 1}
+		requestFunc := func() (interface{}, error) {
+			return client.GetJob(&GetJobInput{test.jobID})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*GetJobOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, want, out.Job, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: want}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestDeleteJob(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		jobID        string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", 0, 0, "job id is required"},
+		{"750", 204, 1, ""},
+		{"750", 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/jobs/ingest/%s", apiVersion, test.jobID)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, deleteMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.DeleteJob(&DeleteJobInput{test.jobID})
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, nil,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestWaitForJob(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	job := func(state string) testserver.ResponseHandler {
+		return &testserver.JSONResponseHandler{StatusCode: http.StatusOK,
+			Body: &JobInfo{ID: "750", State: state}}
+	}
+	errorPage := &testserver.JSONResponseHandler{
+		StatusCode: http.StatusBadRequest,
+		Body:       []interface{}{genericErr},
+	}
+
+	tests := []struct {
+		jobID        string
+		pages        []testserver.ResponseHandler
+		requestCount int
+		errSnippet   string
+		wantState    string
+	}{
+		{"", []testserver.ResponseHandler{errorPage}, 0, "job id is required", ""},
+		{"750", []testserver.ResponseHandler{job(JobStateJobComplete)}, 1, "", JobStateJobComplete},
+		{"750", []testserver.ResponseHandler{job(JobStateUploadComplete), job(JobStateInProgress),
+			job(JobStateFailed)}, 3, "", JobStateFailed},
+		{"750", []testserver.ResponseHandler{job(JobStateInProgress), job(JobStateAborted)}, 2, "",
+			JobStateAborted},
+		{"750", []testserver.ResponseHandler{job(JobStateInProgress), errorPage}, 2, "GENERIC_ERROR", ""},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: test.pages},
+			authTokenValidator, getMethodValidator)
+		server.RequestCount = 0
+
+		out, err := client.WaitForJob(context.Background(), &WaitForJobInput{
+			JobID:        test.jobID,
+			PollInterval: time.Millisecond,
+		})
+		assert.Equal(t, test.requestCount, server.RequestCount, assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		if assert.Nil(t, err, assertMsg) {
+			assert.Equal(t, test.wantState, out.Job.State, assertMsg)
+		}
+	}
+}
+
+func TestGetJobResults(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		jobID        string
+		resultType   string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", ResultTypeSuccessful, 0, 0, "job id is required"},
+		{"750", "allResults", 0, 0, `invalid result type "allResults"`},
+		{"750", ResultTypeSuccessful, 200, 1, ""},
+		{"750", ResultTypeFailed, 200, 1, ""},
+		{"750", ResultTypeUnprocessed, 200, 1, ""},
+		{"750", ResultTypeFailed, 404, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/jobs/ingest/%s/%s", apiVersion, test.jobID,
+			test.resultType)
+		validators := []testserver.RequestValidator{authTokenValidator, emptyQueryValidator,
+			emptyBodyValidator, &testserver.HeaderValidator{Key: "Accept", Value: "text/csv"},
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.GetJobResults(&GetJobResultsInput{JobID: test.jobID,
+				ResultType: test.resultType})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*GetJobResultsOutput)
+			if !assert.True(t, ok, assertMsg) {
+				return
+			}
+			b, err := ioutil.ReadAll(out.Body)
+			assert.Nil(t, err, assertMsg)
+			assert.Nil(t, out.Body.Close(), assertMsg)
+			assert.Contains(t, string(b), "sf__Id", assertMsg)
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode,
+			Body: "sf__Id,sf__Created,Name\n001,true,Acme\n"}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestIngest(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	jobInput := &CreateJobInput{Object: "Account", Operation: OperationInsert}
+	job := func(id, state string) testserver.ResponseHandler {
+		return &testserver.JSONResponseHandler{StatusCode: http.StatusOK,
+			Body: &JobInfo{ID: id, State: state}}
+	}
+	created := &testserver.JSONResponseHandler{StatusCode: http.StatusCreated}
+	errorPage := &testserver.JSONResponseHandler{
+		StatusCode: http.StatusBadRequest,
+		Body:       []interface{}{genericErr},
+	}
+	path := fmt.Sprintf("/services/data/%s/jobs/ingest", apiVersion)
+	createReq := "POST " + path + ` {"object":"Account","operation":"insert","contentType":"CSV"}`
+
+	tests := []struct {
+		job          *CreateJobInput
+		data         string
+		pages        []testserver.ResponseHandler
+		wantRequests []string
+		wantJobs     int
+		errSnippet   string
+	}{
+		{nil, "Name\n", nil, nil, 0, "job is required"},
+		{&CreateJobInput{Object: "Account"}, "Name\n", nil, nil, 0, "invalid operation"},
+		{jobInput, "", nil, nil, 0, "csv header is required"},
+		{jobInput, "Name\n", nil, nil, 0, "csv data has no records"},
+		{jobInput, "Name\n\n\n", nil, nil, 0, "csv data has no records"},
+		{jobInput, "Name\nAA\nBB\n", []testserver.ResponseHandler{job("750A", JobStateOpen), created,
+			job("750A", JobStateUploadComplete)},
+			[]string{createReq, "PUT " + path + "/750A/batches Name\nAA\nBB",
+				"PATCH " + path + `/750A {"state":"UploadComplete"}`}, 1, ""},
+		{jobInput, "Name\nAA\nBB\nCC\n", []testserver.ResponseHandler{job("750A", JobStateOpen), created,
+			job("750A", JobStateUploadComplete), job("750B", JobStateOpen), created,
+			job("750B", JobStateUploadComplete)},
+			[]string{createReq, "PUT " + path + "/750A/batches Name\nAA\nBB",
+				"PATCH " + path + `/750A {"state":"UploadComplete"}`,
+				createReq, "PUT " + path + "/750B/batches Name\nCC",
+				"PATCH " + path + `/750B {"state":"UploadComplete"}`}, 2, ""},
+		{jobInput, "Name\nAA\n", []testserver.ResponseHandler{job("750A", JobStateOpen), errorPage,
+			job("750A", JobStateAborted)},
+			[]string{createReq, "PUT " + path + "/750A/batches Name\nAA",
+				"PATCH " + path + `/750A {"state":"Aborted"}`}, 0, "GENERIC_ERROR"},
+		// the aborted upload may not reach the server, so the requests aren't compared
+		{jobInput, "Name\nAAAAAAAAAAAA\n", []testserver.ResponseHandler{job("750A", JobStateOpen),
+			created, job("750A", JobStateAborted)}, nil, 0, "csv record exceeds the maximum upload size"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		pages := test.pages
+		if pages == nil {
+			pages = []testserver.ResponseHandler{errorPage}
+		}
+		recorder := &requestRecorder{}
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: pages}, authTokenValidator, recorder)
+		server.RequestCount = 0
+
+		out, err := client.ingest(&IngestInput{Job: test.job, Data: strings.NewReader(test.data)}, 13)
+		if test.wantRequests != nil || test.pages == nil {
+			assert.Equal(t, test.wantRequests, recorder.requests, assertMsg)
+		}
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+		} else {
+			assert.Nil(t, err, assertMsg)
+		}
+		if out != nil {
+			assert.Len(t, out.Jobs, test.wantJobs, assertMsg)
+		}
+	}
+}
+
+func TestIngestUploadRejected(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	// the upload is rejected without reading the data, so the chunk writer is blocked
+	data := "Name\n" + strings.Repeat("Acme\n", 1<<20)
+	errorPage := &testserver.JSONResponseHandler{
+		StatusCode: http.StatusBadRequest,
+		Body:       []interface{}{sforceerr.APIError{Message: "Invalid batch", ErrorCode: "InvalidBatch"}},
+	}
+	aborted := &testserver.JSONResponseHandler{StatusCode: http.StatusOK,
+		Body: &JobInfo{ID: "750A", State: JobStateAborted}}
+	handlers := []testserver.ResponseHandler{
+		&testserver.JSONResponseHandler{StatusCode: http.StatusOK,
+			Body: &JobInfo{ID: "750A", State: JobStateOpen}},
+		errorPage, aborted,
+	}
+	server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, "upload rejected",
+		&testserver.ConsecutiveResponseHandler{Handlers: handlers}, authTokenValidator)
+	server.RequestCount = 0
+
+	out, err := client.Ingest(&IngestInput{
+		Job:  &CreateJobInput{Object: "Account", Operation: OperationInsert},
+		Data: strings.NewReader(data),
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "InvalidBatch")
+	}
+	assert.Empty(t, out.Jobs)
+	assert.Equal(t, 3, server.RequestCount)
+}
+
+func assertRequest(t *testing.T, assertMsg string, server *testserver.Server, wantErr string,
+	invokeFunc func() (interface{}, error), successFunc func(interface{}),
+	expectedRequestCount int, validators []testserver.RequestValidator,
+	respHandler *testserver.JSONResponseHandler) {
+	shouldErr := wantErr != ""
+	// set server response
+	if shouldErr {
+		respHandler.Body = genericErr
+	}
+	server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg, respHandler, validators...)
+
+	// invoke request
+	server.RequestCount = 0 // reset counter
+	out, err := invokeFunc()
+
+	// assertions
+	assert.Equal(t, expectedRequestCount, server.RequestCount, assertMsg)
+	if shouldErr {
+		if assert.Error(t, err, assertMsg) {
+			assert.Contains(t, err.Error(), wantErr, assertMsg)
+		}
+	} else {
+		assert.Nil(t, err, assertMsg)
+		if successFunc != nil {
+			successFunc(out)
+		}
+	}
+}
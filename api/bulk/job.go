@@ -0,0 +1,94 @@
+package bulkapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+// getJob retrieves the job under the jobs path.
+func (c *Client) getJob(jobsPath, jobID string) (*JobInfo, error) {
+	// validate parameters
+	if jobID == "" {
+		return nil, errors.New("job id is required")
+	}
+
+	var job JobInfo
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodGet,
+		APIPath: path.Join(jobsPath, jobID),
+	}, request.JSONResult, &job, http.StatusOK)
+	return &job, req.Send()
+}
+
+// deleteJob deletes the job under the jobs path.
+func (c *Client) deleteJob(jobsPath, jobID string) error {
+	// validate parameters
+	if jobID == "" {
+		return errors.New("job id is required")
+	}
+
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodDelete,
+		APIPath: path.Join(jobsPath, jobID),
+	}, request.JSONResult, nil, http.StatusNoContent)
+	return req.Send()
+}
+
+// waitForJob polls the job under the jobs path until it is complete, failed or aborted.
+func (c *Client) waitForJob(ctx context.Context, jobsPath, jobID string,
+	pollInterval time.Duration) (*JobInfo, error) {
+	// validate parameters
+	if jobID == "" {
+		return nil, errors.New("job id is required")
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+
+	for {
+		job, err := c.getJob(jobsPath, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if job.Done() {
+			return job, nil
+		}
+
+		// wait for next poll
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// setJobState sets the state of the job under the jobs path.
+func (c *Client) setJobState(jobsPath, jobID, state string) (*JobInfo, error) {
+	// validate parameters
+	if jobID == "" {
+		return nil, errors.New("job id is required")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(&JobInfo{State: state}); err != nil {
+		return nil, fmt.Errorf("couldn't marshal job: %v", err)
+	}
+	var job JobInfo
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPatch,
+		APIPath: path.Join(jobsPath, jobID),
+		Body:    buf,
+	}, request.JSONResult, &job, http.StatusOK)
+	return &job, req.Send()
+}
@@ -0,0 +1,359 @@
+package bulkapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+const queryJobPath = "/services/data/%s/jobs/query/"
+
+// query operations
+const (
+	OperationQuery    = "query"
+	OperationQueryAll = "queryAll"
+)
+
+// CreateQueryJobInput stores the input for creating a query job. The operation is query, or
+// queryAll to include deleted and archived records, and defaults to query. The line ending
+// (LF or CRLF) and column delimiter (e.g. COMMA, TAB) of the CSV results default to LF and
+// COMMA.
+type CreateQueryJobInput struct {
+	Query           string
+	Operation       string
+	LineEnding      string
+	ColumnDelimiter string
+}
+
+// CreateQueryJobOutput stores the output after creating a query job.
+type CreateQueryJobOutput struct {
+	Job *JobInfo
+}
+
+// CreateQueryJob creates a query job using the Salesforce Bulk API 2.0. The query is
+// processed asynchronously, see WaitForQueryJob to wait for the results.
+func (c *Client) CreateQueryJob(input *CreateQueryJobInput) (*CreateQueryJobOutput, error) {
+	// validate parameters
+	if input.Query == "" {
+		return nil, errors.New("query is required")
+	}
+	operation := input.Operation
+	switch operation {
+	case "":
+		operation = OperationQuery
+	case OperationQuery, OperationQueryAll:
+	default:
+		return nil, fmt.Errorf("invalid operation %q", operation)
+	}
+
+	buf := &bytes.Buffer{}
+	err := json.NewEncoder(buf).Encode(&JobInfo{
+		Operation:       operation,
+		Query:           input.Query,
+		ContentType:     "CSV",
+		LineEnding:      input.LineEnding,
+		ColumnDelimiter: input.ColumnDelimiter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal job: %v", err)
+	}
+	var job JobInfo
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPost,
+		APIPath: c.queryJobPath(),
+		Body:    buf,
+	}, request.JSONResult, &job, http.StatusOK)
+	return &CreateQueryJobOutput{&job}, req.Send()
+}
+
+// GetQueryJobInput stores the input for retrieving a query job.
+type GetQueryJobInput struct {
+	JobID string
+}
+
+// GetQueryJobOutput stores the output after retrieving a query job.
+type GetQueryJobOutput struct {
+	Job *JobInfo
+}
+
+// GetQueryJob retrieves the state and progress of the query job from the Salesforce Bulk API
+// 2.0.
+func (c *Client) GetQueryJob(input *GetQueryJobInput) (*GetQueryJobOutput, error) {
+	job, err := c.getJob(c.queryJobPath(), input.JobID)
+	return &GetQueryJobOutput{job}, err
+}
+
+// AbortQueryJobInput stores the input for aborting a query job.
+type AbortQueryJobInput struct {
+	JobID string
+}
+
+// AbortQueryJobOutput stores the output after aborting a query job.
+type AbortQueryJobOutput struct {
+	Job *JobInfo
+}
+
+// AbortQueryJob aborts the query job using the Salesforce Bulk API 2.0.
+func (c *Client) AbortQueryJob(input *AbortQueryJobInput) (*AbortQueryJobOutput, error) {
+	job, err := c.setJobState(c.queryJobPath(), input.JobID, JobStateAborted)
+	return &AbortQueryJobOutput{job}, err
+}
+
+// DeleteQueryJobInput stores the input for deleting a query job.
+type DeleteQueryJobInput struct {
+	JobID string
+}
+
+// DeleteQueryJobOutput stores the output after deleting a query job.
+type DeleteQueryJobOutput struct {
+}
+
+// DeleteQueryJob deletes the query job and its results using the Salesforce Bulk API 2.0. The
+// job must be complete, failed or aborted.
+func (c *Client) DeleteQueryJob(input *DeleteQueryJobInput) (*DeleteQueryJobOutput, error) {
+	return &DeleteQueryJobOutput{}, c.deleteJob(c.queryJobPath(), input.JobID)
+}
+
+// WaitForQueryJobInput stores the input for waiting for a query job to be processed. The job
+// is polled every poll interval, which defaults to 5 seconds.
+type WaitForQueryJobInput struct {
+	JobID        string
+	PollInterval time.Duration
+}
+
+// WaitForQueryJobOutput stores the output after a query job was processed.
+type WaitForQueryJobOutput struct {
+	Job *JobInfo
+}
+
+// WaitForQueryJob polls the query job until it is complete, failed or aborted. Waiting stops
+// if the context is done.
+func (c *Client) WaitForQueryJob(ctx context.Context,
+	input *WaitForQueryJobInput) (*WaitForQueryJobOutput, error) {
+	job, err := c.waitForJob(ctx, c.queryJobPath(), input.JobID, input.PollInterval)
+	if err != nil {
+		return nil, err
+	}
+	return &WaitForQueryJobOutput{job}, nil
+}
+
+// GetQueryJobResultsInput stores the input for downloading a page of the results of a query
+// job. The locator of the next page is returned with each page, and the first page is
+// downloaded if the locator is empty. The max records limits the records of the page, and
+// the Salesforce default is used if it is 0.
+type GetQueryJobResultsInput struct {
+	JobID      string
+	Locator    string
+	MaxRecords int
+}
+
+// GetQueryJobResultsOutput stores the output after downloading a page of the results of a
+// query job. The body is CSV data including the header, and the caller must close the body.
+// The locator is empty if this is the last page.
+type GetQueryJobResultsOutput struct {
+	Body            io.ReadCloser
+	Locator         string
+	NumberOfRecords int
+}
+
+// GetQueryJobResults downloads a page of the results of the complete query job from the
+// Salesforce Bulk API 2.0. The results are streamed instead of read into memory.
+func (c *Client) GetQueryJobResults(input *GetQueryJobResultsInput) (*GetQueryJobResultsOutput, error) {
+	// validate parameters
+	if input.JobID == "" {
+		return nil, errors.New("job id is required")
+	}
+	if input.MaxRecords < 0 {
+		return nil, errors.New("max records must not be negative")
+	}
+
+	query := url.Values{}
+	if input.Locator != "" {
+		query.Set("locator", input.Locator)
+	}
+	if input.MaxRecords > 0 {
+		query.Set("maxRecords", strconv.Itoa(input.MaxRecords))
+	}
+	var body io.ReadCloser
+	req := c.newRequest(&request.Operation{
+		Method:   http.MethodGet,
+		APIPath:  c.queryJobPath(input.JobID, "results"),
+		RawQuery: query.Encode(),
+		Header:   http.Header{"Accept": {"text/csv"}},
+	}, request.StreamResult, &body, http.StatusOK)
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+
+	header := req.Response().Header
+	locator := header.Get("Sforce-Locator")
+	if locator == "null" {
+		locator = ""
+	}
+	numRecords, _ := strconv.Atoi(header.Get("Sforce-NumberOfRecords"))
+	return &GetQueryJobResultsOutput{body, locator, numRecords}, nil
+}
+
+// QueryJobResultsInput stores the input for streaming the results of a query job. The max
+// records limits the records of each page, and the Salesforce default is used if it is 0.
+type QueryJobResultsInput struct {
+	JobID      string
+	MaxRecords int
+}
+
+// QueryJobResultsOutput stores the output after streaming the results of a query job. The
+// body is CSV data with a single header, and the caller must close the body.
+type QueryJobResultsOutput struct {
+	Body io.ReadCloser
+}
+
+// QueryJobResults streams every page of the results of the complete query job from the
+// Salesforce Bulk API 2.0 as a single CSV. The next page is downloaded when the body has
+// been read up to the end of the current page. Use NewSObjectReader to decode the records.
+func (c *Client) QueryJobResults(input *QueryJobResultsInput) (*QueryJobResultsOutput, error) {
+	page, err := c.GetQueryJobResults(&GetQueryJobResultsInput{
+		JobID:      input.JobID,
+		MaxRecords: input.MaxRecords,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &QueryJobResultsOutput{&queryResultsReader{
+		c:          c,
+		jobID:      input.JobID,
+		maxRecords: input.MaxRecords,
+		body:       page.Body,
+		r:          bufio.NewReader(page.Body),
+		locator:    page.Locator,
+	}}, nil
+}
+
+// QueryInput stores the input for executing a SOQL query with a query job. The operation is
+// query or queryAll, and defaults to query. The job is polled every poll interval, which
+// defaults to 5 seconds. The max records limits the records of each page of the results.
+type QueryInput struct {
+	Query        string
+	Operation    string
+	PollInterval time.Duration
+	MaxRecords   int
+}
+
+// QueryOutput stores the output after executing a SOQL query with a query job. The body is
+// CSV data with a single header, and the caller must close the body.
+type QueryOutput struct {
+	Job  *JobInfo
+	Body io.ReadCloser
+}
+
+// Query creates a query job for the SOQL query, waits for the job to complete and streams
+// its results. Waiting stops if the context is done. The job is not deleted, see
+// DeleteQueryJob to delete the job once the results are read.
+func (c *Client) Query(ctx context.Context, input *QueryInput) (*QueryOutput, error) {
+	created, err := c.CreateQueryJob(&CreateQueryJobInput{
+		Query:     input.Query,
+		Operation: input.Operation,
+	})
+	if err != nil {
+		return nil, err
+	}
+	done, err := c.WaitForQueryJob(ctx, &WaitForQueryJobInput{
+		JobID:        created.Job.ID,
+		PollInterval: input.PollInterval,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if done.Job.State != JobStateJobComplete {
+		return &QueryOutput{Job: done.Job}, fmt.Errorf("query job %s is %s: %s", done.Job.ID,
+			done.Job.State, done.Job.ErrorMessage)
+	}
+	results, err := c.QueryJobResults(&QueryJobResultsInput{
+		JobID:      done.Job.ID,
+		MaxRecords: input.MaxRecords,
+	})
+	if err != nil {
+		return &QueryOutput{Job: done.Job}, err
+	}
+	return &QueryOutput{done.Job, results.Body}, nil
+}
+
+// queryResultsReader reads the pages of the results of a query job as a single CSV. The
+// header of each page after the first is skipped.
+type queryResultsReader struct {
+	c          *Client
+	jobID      string
+	maxRecords int
+	body       io.ReadCloser // body of the current page
+	r          *bufio.Reader
+	locator    string // locator of the next page
+}
+
+// Read implements the io.Reader interface.
+func (qr *queryResultsReader) Read(p []byte) (int, error) {
+	for {
+		if qr.body == nil {
+			return 0, io.EOF
+		}
+		n, err := qr.r.Read(p)
+		if err != io.EOF {
+			return n, err
+		}
+		if err := qr.nextPage(); err != nil {
+			return n, err
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// nextPage closes the current page and downloads the next page, if any.
+func (qr *queryResultsReader) nextPage() error {
+	err := qr.body.Close()
+	qr.body = nil
+	if err != nil || qr.locator == "" {
+		return err
+	}
+
+	page, err := qr.c.GetQueryJobResults(&GetQueryJobResultsInput{
+		JobID:      qr.jobID,
+		Locator:    qr.locator,
+		MaxRecords: qr.maxRecords,
+	})
+	if err != nil {
+		return err
+	}
+	qr.body, qr.r, qr.locator = page.Body, bufio.NewReader(page.Body), page.Locator
+
+	// skip header
+	if _, err := qr.r.ReadString('\n'); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// Close implements the io.Closer interface.
+func (qr *queryResultsReader) Close() error {
+	if qr.body == nil {
+		return nil
+	}
+	err := qr.body.Close()
+	qr.body = nil
+	return err
+}
+
+// queryJobPath returns the api path for the query job resource joined with the elements.
+func (c *Client) queryJobPath(elem ...string) string {
+	return path.Join(append([]string{fmt.Sprintf(queryJobPath, c.sess.APIVersion)}, elem...)...)
+}
@@ -0,0 +1,371 @@
+package bulkapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+// csvResponseHandler writes a page of CSV query results to the response writer.
+type csvResponseHandler struct {
+	locator    string
+	numRecords int
+	body       string
+}
+
+// Handle implements the ResponseHandler interface.
+func (h *csvResponseHandler) Handle(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Sforce-Locator", h.locator)
+	w.Header().Set("Sforce-NumberOfRecords", strconv.Itoa(h.numRecords))
+	w.WriteHeader(http.StatusOK)
+	_, err := io.WriteString(w, h.body)
+	return err
+}
+
+func TestCreateQueryJob(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	soql := "SELECT Id, Name FROM Account"
+	tests := []struct {
+		input        *CreateQueryJobInput
+		wantBody     map[string]interface{}
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{&CreateQueryJobInput{Query: ""}, nil, 0, 0, "query is required"},
+		{&CreateQueryJobInput{Query: soql, Operation: OperationInsert}, nil, 0, 0, `invalid operation "insert"`},
+		{&CreateQueryJobInput{Query: soql},
+			map[string]interface{}{"operation": "query", "query": soql, "contentType": "CSV"},
+			200, 1, ""},
+		{&CreateQueryJobInput{Query: soql, Operation: OperationQueryAll, LineEnding: "CRLF",
+			ColumnDelimiter: "TAB"},
+			map[string]interface{}{"operation": "queryAll", "query": soql, "contentType": "CSV",
+				"lineEnding": "CRLF", "columnDelimiter": "TAB"},
+			200, 1, ""},
+		{&CreateQueryJobInput{Query: soql},
+			map[string]interface{}{"operation": "query", "query": soql, "contentType": "CSV"},
+			400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/jobs/query", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.JSONBodyValidator{Body: test.wantBody},
+			&testserver.PathValidator{Path: path}, postMethodValidator}
+
+		want := &JobInfo{ID: "750", Object: "Account", Operation: OperationQuery, State: JobStateUploadComplete}
+		requestFunc := func() (interface{}, error) {
+			return client.CreateQueryJob(test.input)
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*CreateQueryJobOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, want, out.Job, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: want}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestQueryJobRequests(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	want := &JobInfo{ID: "750", State: JobStateAborted}
+	tests := []struct {
+		jobID        string
+		method       string
+		wantBody     interface{}
+		invoke       func(jobID string) (interface{}, error)
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", http.MethodGet, nil, func(jobID string) (interface{}, error) {
+			out, err := client.GetQueryJob(&GetQueryJobInput{jobID})
+			return out.Job, err
+		}, 0, 0, "job id is required"},
+		{"750", http.MethodGet, nil, func(jobID string) (interface{}, error) {
+			out, err := client.GetQueryJob(&GetQueryJobInput{jobID})
+			return out.Job, err
+		}, 200, 1, ""},
+		{"750", http.MethodGet, nil, func(jobID string) (interface{}, error) {
+			out, err := client.GetQueryJob(&GetQueryJobInput{jobID})
+			return out.Job, err
+		}, 404, 1, "GENERIC_ERROR"},
+		{"", http.MethodPatch, nil, func(jobID string) (interface{}, error) {
+			out, err := client.AbortQueryJob(&AbortQueryJobInput{jobID})
+			return out.Job, err
+		}, 0, 0, "job id is required"},
+		{"750", http.MethodPatch, map[string]string{"state": "Aborted"}, func(jobID string) (interface{}, error) {
+			out, err := client.AbortQueryJob(&AbortQueryJobInput{jobID})
+			return out.Job, err
+		}, 200, 1, ""},
+		{"", http.MethodDelete, nil, func(jobID string) (interface{}, error) {
+			_, err := client.DeleteQueryJob(&DeleteQueryJobInput{jobID})
+			return want, err
+		}, 0, 0, "job id is required"},
+		{"750", http.MethodDelete, nil, func(jobID string) (interface{}, error) {
+			_, err := client.DeleteQueryJob(&DeleteQueryJobInput{jobID})
+			return want, err
+		}, 204, 1, ""},
+		{"750", http.MethodDelete, nil, func(jobID string) (interface{}, error) {
+			_, err := client.DeleteQueryJob(&DeleteQueryJobInput{jobID})
+			return want, err
+		}, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %s %s %v %d", test.method, test.jobID, test.wantBody, test.statusCode)
+		path := fmt.Sprintf("/services/data/%s/jobs/query/%s", apiVersion, test.jobID)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.JSONBodyValidator{Body: test.wantBody},
+			&testserver.PathValidator{Path: path}, &testserver.MethodValidator{Method: test.method}}
+
+		requestFunc := func() (interface{}, error) {
+			return test.invoke(test.jobID)
+		}
+		successFunc := func(res interface{}) {
+			assert.Equal(t, want, res, assertMsg)
+		}
+		var body interface{} = want
+		if test.method == http.MethodDelete {
+			body = nil
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: body}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestWaitForQueryJob(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	job := func(state string) testserver.ResponseHandler {
+		return &testserver.JSONResponseHandler{StatusCode: http.StatusOK,
+			Body: &JobInfo{ID: "750", State: state}}
+	}
+	path := fmt.Sprintf("GET /services/data/%s/jobs/query/750", apiVersion)
+
+	tests := []struct {
+		jobID        string
+		pages        []testserver.ResponseHandler
+		wantRequests []string
+		errSnippet   string
+		wantState    string
+	}{
+		{"", []testserver.ResponseHandler{job(JobStateJobComplete)}, nil, "job id is required", ""},
+		{"750", []testserver.ResponseHandler{job(JobStateUploadComplete), job(JobStateInProgress),
+			job(JobStateJobComplete)}, []string{path, path, path}, "", JobStateJobComplete},
+		{"750", []testserver.ResponseHandler{job(JobStateFailed)}, []string{path}, "", JobStateFailed},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		recorder := &requestRecorder{}
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: test.pages}, authTokenValidator, recorder)
+
+		out, err := client.WaitForQueryJob(context.Background(), &WaitForQueryJobInput{
+			JobID:        test.jobID,
+			PollInterval: time.Millisecond,
+		})
+		assert.Equal(t, test.wantRequests, recorder.requests, assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		if assert.Nil(t, err, assertMsg) {
+			assert.Equal(t, test.wantState, out.Job.State, assertMsg)
+		}
+	}
+}
+
+func TestGetQueryJobResults(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	body := "\"Id\",\"Name\"\n\"001\",\"Acme\"\n"
+	errorPage := &testserver.JSONResponseHandler{
+		StatusCode: http.StatusBadRequest,
+		Body:       []interface{}{genericErr},
+	}
+	tests := []struct {
+		input        *GetQueryJobResultsInput
+		handler      testserver.ResponseHandler
+		wantQuery    url.Values
+		requestCount int
+		errSnippet   string
+		wantLocator  string
+	}{
+		{&GetQueryJobResultsInput{JobID: ""}, nil, nil, 0, "job id is required", ""},
+		{&GetQueryJobResultsInput{JobID: "750", MaxRecords: -1}, nil, nil, 0,
+			"max records must not be negative", ""},
+		{&GetQueryJobResultsInput{JobID: "750"}, &csvResponseHandler{"MTAwMDA", 1, body},
+			url.Values{}, 1, "", "MTAwMDA"},
+		{&GetQueryJobResultsInput{JobID: "750", Locator: "MTAwMDA", MaxRecords: 500},
+			&csvResponseHandler{"null", 1, body},
+			url.Values{"locator": {"MTAwMDA"}, "maxRecords": {"500"}}, 1, "", ""},
+		{&GetQueryJobResultsInput{JobID: "750"}, errorPage, url.Values{}, 1, "GENERIC_ERROR", ""},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/jobs/query/%s/results", apiVersion, test.input.JobID)
+		handler := test.handler
+		if handler == nil {
+			handler = errorPage
+		}
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg, handler,
+			authTokenValidator, emptyBodyValidator, &testserver.QueryValidator{Query: test.wantQuery},
+			&testserver.HeaderValidator{Key: "Accept", Value: "text/csv"},
+			&testserver.PathValidator{Path: path}, getMethodValidator)
+		server.RequestCount = 0
+
+		out, err := client.GetQueryJobResults(test.input)
+		assert.Equal(t, test.requestCount, server.RequestCount, assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, assertMsg) {
+			continue
+		}
+		b, err := ioutil.ReadAll(out.Body)
+		assert.Nil(t, err, assertMsg)
+		assert.Nil(t, out.Body.Close(), assertMsg)
+		assert.Equal(t, body, string(b), assertMsg)
+		assert.Equal(t, test.wantLocator, out.Locator, assertMsg)
+		assert.Equal(t, 1, out.NumberOfRecords, assertMsg)
+	}
+}
+
+func TestQueryJobResults(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	errorPage := &testserver.JSONResponseHandler{
+		StatusCode: http.StatusBadRequest,
+		Body:       []interface{}{genericErr},
+	}
+	path := fmt.Sprintf("GET /services/data/%s/jobs/query/750/results", apiVersion)
+
+	tests := []struct {
+		maxRecords   int
+		pages        []testserver.ResponseHandler
+		wantRequests []string
+		wantBody     string
+		errSnippet   string
+	}{
+		{0, []testserver.ResponseHandler{&csvResponseHandler{"null", 2, "Id\n001\n002\n"}},
+			[]string{path}, "Id\n001\n002\n", ""},
+		{1, []testserver.ResponseHandler{&csvResponseHandler{"A", 1, "Id\n001\n"},
+			&csvResponseHandler{"B", 1, "Id\r\n002\r\n"}, &csvResponseHandler{"null", 0, "Id\n"}},
+			[]string{path + "?maxRecords=1", path + "?locator=A&maxRecords=1",
+				path + "?locator=B&maxRecords=1"}, "Id\n001\n002\r\n", ""},
+		{0, []testserver.ResponseHandler{errorPage}, []string{path}, "", "GENERIC_ERROR"},
+		{0, []testserver.ResponseHandler{&csvResponseHandler{"A", 1, "Id\n001\n"}, errorPage},
+			[]string{path, path + "?locator=A"}, "Id\n001\n", "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		recorder := &requestRecorder{}
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: test.pages}, authTokenValidator, recorder)
+
+		var b []byte
+		out, err := client.QueryJobResults(&QueryJobResultsInput{JobID: "750", MaxRecords: test.maxRecords})
+		if err == nil {
+			b, err = ioutil.ReadAll(out.Body)
+			assert.Nil(t, out.Body.Close(), assertMsg)
+		}
+		assert.Equal(t, test.wantRequests, recorder.requests, assertMsg)
+		assert.Equal(t, test.wantBody, string(b), assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+		} else {
+			assert.Nil(t, err, assertMsg)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	job := func(state string) testserver.ResponseHandler {
+		return &testserver.JSONResponseHandler{StatusCode: http.StatusOK,
+			Body: &JobInfo{ID: "750", State: state, ErrorMessage: "INVALID_FIELD"}}
+	}
+	path := fmt.Sprintf("/services/data/%s/jobs/query", apiVersion)
+	soql := "SELECT Id FROM Account"
+	createReq := "POST " + path + ` {"operation":"query","query":"SELECT Id FROM Account","contentType":"CSV"}`
+
+	tests := []struct {
+		query        string
+		pages        []testserver.ResponseHandler
+		wantRequests []string
+		wantBody     string
+		errSnippet   string
+	}{
+		{"", []testserver.ResponseHandler{job(JobStateUploadComplete)}, nil, "", "query is required"},
+		{soql, []testserver.ResponseHandler{job(JobStateUploadComplete), job(JobStateInProgress),
+			job(JobStateJobComplete), &csvResponseHandler{"null", 1, "Id\n001\n"}},
+			[]string{createReq, "GET " + path + "/750", "GET " + path + "/750",
+				"GET " + path + "/750/results?maxRecords=100"}, "Id\n001\n", ""},
+		{soql, []testserver.ResponseHandler{job(JobStateUploadComplete), job(JobStateFailed)},
+			[]string{createReq, "GET " + path + "/750"}, "", "query job 750 is Failed: INVALID_FIELD"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		recorder := &requestRecorder{}
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: test.pages}, authTokenValidator, recorder)
+
+		var b []byte
+		out, err := client.Query(context.Background(), &QueryInput{
+			Query:        test.query,
+			PollInterval: time.Millisecond,
+			MaxRecords:   100,
+		})
+		if err == nil {
+			b, err = ioutil.ReadAll(out.Body)
+			assert.Nil(t, out.Body.Close(), assertMsg)
+		}
+		assert.Equal(t, test.wantRequests, recorder.requests, assertMsg)
+		assert.Equal(t, test.wantBody, string(b), assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+		} else {
+			assert.Nil(t, err, assertMsg)
+		}
+	}
+}
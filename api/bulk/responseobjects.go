@@ -0,0 +1,37 @@
+package bulkapi
+
+// JobInfo is the state and progress of a Bulk API 2.0 job. The processing time is in
+// milliseconds.
+type JobInfo struct {
+	ID                     string  `json:"id,omitempty"`
+	Object                 string  `json:"object,omitempty"`
+	Operation              string  `json:"operation,omitempty"`
+	Query                  string  `json:"query,omitempty"`
+	State                  string  `json:"state,omitempty"`
+	ExternalIDFieldName    string  `json:"externalIdFieldName,omitempty"`
+	ContentType            string  `json:"contentType,omitempty"`
+	LineEnding             string  `json:"lineEnding,omitempty"`
+	ColumnDelimiter        string  `json:"columnDelimiter,omitempty"`
+	ConcurrencyMode        string  `json:"concurrencyMode,omitempty"`
+	ContentURL             string  `json:"contentUrl,omitempty"`
+	JobType                string  `json:"jobType,omitempty"`
+	APIVersion             float64 `json:"apiVersion,omitempty"`
+	CreatedByID            string  `json:"createdById,omitempty"`
+	CreatedDate            string  `json:"createdDate,omitempty"`
+	SystemModstamp         string  `json:"systemModstamp,omitempty"`
+	NumberRecordsProcessed int     `json:"numberRecordsProcessed,omitempty"`
+	NumberRecordsFailed    int     `json:"numberRecordsFailed,omitempty"`
+	Retries                int     `json:"retries,omitempty"`
+	TotalProcessingTime    int     `json:"totalProcessingTime,omitempty"`
+	ErrorMessage           string  `json:"errorMessage,omitempty"`
+}
+
+// Done returns true if the job is complete, failed or aborted.
+func (j *JobInfo) Done() bool {
+	switch j.State {
+	case JobStateJobComplete, JobStateFailed, JobStateAborted:
+		return true
+	default:
+		return false
+	}
+}
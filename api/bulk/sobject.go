@@ -0,0 +1,82 @@
+package bulkapi
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+)
+
+// SObjectReader decodes the records of CSV query results into SObjects. The first record is
+// the header. Relationship fields in the header (e.g. Account.Name) are added as nested
+// SObjects, and empty values are decoded as nil since the Bulk API doesn't distinguish null
+// from empty strings.
+type SObjectReader struct {
+	// Comma is the column delimiter of the CSV data. It defaults to a comma and must be set
+	// before the first call to Read (e.g. '\t' for results of a job with a TAB delimiter).
+	Comma rune
+
+	r      *csv.Reader
+	header []string
+}
+
+// NewSObjectReader returns a new SObjectReader that reads from r.
+func NewSObjectReader(r io.Reader) *SObjectReader {
+	return &SObjectReader{Comma: ',', r: csv.NewReader(r)}
+}
+
+// Read reads the next record as a SObject. It returns io.EOF when there are no more records.
+func (sr *SObjectReader) Read() (restapi.SObject, error) {
+	if sr.header == nil {
+		sr.r.Comma = sr.Comma
+		header, err := sr.r.Read()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read csv header: %v", err)
+		}
+		// ensure every field is valid before decoding records
+		if _, err := newSObject(header, make([]string, len(header))); err != nil {
+			return nil, fmt.Errorf("invalid csv header: %v", err)
+		}
+		sr.header = header
+	}
+
+	record, err := sr.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	return newSObject(sr.header, record)
+}
+
+// ReadAll reads the remaining records as SObjects.
+func (sr *SObjectReader) ReadAll() ([]restapi.SObject, error) {
+	var sobjs []restapi.SObject
+	for {
+		sobj, err := sr.Read()
+		if err == io.EOF {
+			return sobjs, nil
+		}
+		if err != nil {
+			return sobjs, err
+		}
+		sobjs = append(sobjs, sobj)
+	}
+}
+
+// newSObject returns the SObject with the values of the record for the fields of the header.
+func newSObject(header, record []string) (restapi.SObject, error) {
+	sobj := restapi.SObject{}
+	for i, field := range header {
+		var v interface{}
+		if record[i] != "" {
+			v = record[i]
+		}
+		if err := sobj.AddDottedField(field, v); err != nil {
+			return nil, err
+		}
+	}
+	return sobj, nil
+}
@@ -0,0 +1,59 @@
+package bulkapi
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSObjectReader(t *testing.T) {
+	tests := []struct {
+		data       string
+		comma      rune
+		want       []restapi.SObject
+		errSnippet string
+	}{
+		{"", ',', nil, ""},
+		{"Id,Name\n", ',', nil, ""},
+		{"Id,Name\n001,Acme\n002,\n", ',', []restapi.SObject{
+			{"Id": "001", "Name": "Acme"},
+			{"Id": "002", "Name": nil},
+		}, ""},
+		{"\"Id\",\"Account.Name\",\"Account.Owner.Name\"\r\n\"003\",\"Acme, Inc.\",\"John \"\"JD\"\" Doe\"\r\n",
+			',', []restapi.SObject{
+				{"Id": "003", "Account": map[string]interface{}{"Name": "Acme, Inc.",
+					"Owner": map[string]interface{}{"Name": `John "JD" Doe`}}},
+			}, ""},
+		{"Id\tDescription\n001\t\"line 1\nline 2\"\n", '\t', []restapi.SObject{
+			{"Id": "001", "Description": "line 1\nline 2"},
+		}, ""},
+		{"Id,Account..Name\n001,Acme\n", ',', nil, "invalid csv header"},
+		{"Id,\"Name\n", ',', nil, "couldn't read csv header"},
+		{"Id,Name\n001,Acme\n002\n", ',', []restapi.SObject{
+			{"Id": "001", "Name": "Acme"},
+		}, "wrong number of fields"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		sr := NewSObjectReader(strings.NewReader(test.data))
+		sr.Comma = test.comma
+		got, err := sr.ReadAll()
+		assert.Equal(t, test.want, got, assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		assert.Nil(t, err, assertMsg)
+
+		// reader stays at EOF
+		_, err = sr.Read()
+		assert.Equal(t, io.EOF, err, assertMsg)
+	}
+}
@@ -0,0 +1,33 @@
+package restapi
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const apexRestPath = "/services/apexrest/"
+
+// ApexRest invokes the custom Apex REST endpoint (@RestResource) at the path relative to
+// /services/apexrest/ (e.g. Account/001). The body is sent as is if it is an io.Reader,
+// otherwise it is marshalled as json. The json response is unmarshalled into out if out is
+// not nil and the response is not empty. A 200, 201, 202 or 204 status code is successful.
+func (c *Client) ApexRest(method, apexPath string, query url.Values, body, out interface{}) error {
+	// validate parameters
+	if method == "" {
+		return errors.New("method is required")
+	}
+	if strings.Trim(apexPath, "/") == "" {
+		return errors.New("apex rest path is required")
+	}
+
+	resp, err := c.Do(method, path.Join(apexRestPath, apexPath), query, body)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return resp.Decode(out)
+}
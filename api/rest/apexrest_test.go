@@ -0,0 +1,68 @@
+package restapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApexRest(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		method       string
+		path         string
+		query        url.Values
+		body         interface{}
+		wantBody     interface{}
+		respBody     interface{}
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", "Account", nil, nil, nil, nil, 0, 0, "method is required"},
+		{"GET", "/", nil, nil, nil, nil, 0, 0, "apex rest path is required"},
+		{"get", "/Account/001", url.Values{"fields": {"Name"}}, nil, nil,
+			map[string]interface{}{"Id": "001"}, 200, 1, ""},
+		{"POST", "Account", url.Values{}, map[string]interface{}{"Name": "Acme"},
+			map[string]interface{}{"Name": "Acme"}, map[string]interface{}{"Id": "001"}, 201, 1, ""},
+		{"PUT", "Account", url.Values{}, strings.NewReader(`{"Name":"Acme"}`),
+			map[string]interface{}{"Name": "Acme"}, nil, 204, 1, ""},
+		{"DELETE", "Account/001", url.Values{}, nil, nil, nil, 200, 1, ""},
+		{"GET", "Account/001", url.Values{}, nil, nil, nil, 404, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := "/services/apexrest/" + strings.Trim(test.path, "/")
+		query := test.query
+		if query == nil {
+			query = url.Values{}
+		}
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: query}, &testserver.PathValidator{Path: path},
+			&testserver.MethodValidator{Method: strings.ToUpper(test.method)},
+			&testserver.JSONBodyValidator{Body: test.wantBody}}
+
+		var out map[string]interface{}
+		requestFunc := func() (interface{}, error) {
+			return nil, client.ApexRest(test.method, test.path, test.query, test.body, &out)
+		}
+		successFunc := func(interface{}) {
+			if test.respBody == nil {
+				assert.Nil(t, out, assertMsg)
+			} else {
+				assert.Equal(t, test.respBody, out, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: test.respBody}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
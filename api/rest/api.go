@@ -14,8 +14,9 @@ import (
 )
 
 const (
-	sObjectPath = "/services/data/%s/sobjects/"
-	queryPath   = "/services/data/%s/query/"
+	sObjectPath  = "/services/data/%s/sobjects/"
+	queryPath    = "/services/data/%s/query/"
+	queryAllPath = "/services/data/%s/queryAll/"
 )
 
 // CreateSObjectInput stores the input for creating a SObject.
@@ -32,11 +33,8 @@ type CreateSObjectOutput struct {
 // CreateSObject creates the SObject using the Salesforce API.
 func (c *Client) CreateSObject(input *CreateSObjectInput) (*CreateSObjectOutput, error) {
 	// validate parameters
-	if isInvalidFieldName(input.SObjectName) {
-		return nil, errors.New("invalid sobject name")
-	}
-	if len(input.SObject) == 0 {
-		return nil, errors.New("sobject value is required")
+	if err := input.validate(); err != nil {
+		return nil, err
 	}
 
 	buf := &bytes.Buffer{}
@@ -45,14 +43,23 @@ func (c *Client) CreateSObject(input *CreateSObjectInput) (*CreateSObjectOutput,
 	}
 	var result UpsertResult
 	req := c.newRequest(&request.Operation{
-		Method: http.MethodPost,
-		APIPath: path.Join(fmt.Sprintf(sObjectPath, c.sess.APIVersion),
-			input.SObjectName),
-		Body: buf,
+		Method:  http.MethodPost,
+		APIPath: c.sObjectPath(input.SObjectName),
+		Body:    buf,
 	}, request.JSONResult, &result, http.StatusCreated)
 	return &CreateSObjectOutput{&result}, req.Send()
 }
 
+func (input *CreateSObjectInput) validate() error {
+	if isInvalidFieldName(input.SObjectName) {
+		return errors.New("invalid sobject name")
+	}
+	if len(input.SObject) == 0 {
+		return errors.New("sobject value is required")
+	}
+	return nil
+}
+
 // GetSObjectInput stores the input for retrieving a SObject by ID.
 type GetSObjectInput struct {
 	SObjectName string
@@ -68,33 +75,30 @@ type GetSObjectOutput struct {
 // GetSObject retrieves a SObject from Salesforce.
 func (c *Client) GetSObject(input *GetSObjectInput) (*GetSObjectOutput, error) {
 	// validate parameters
-	if isInvalidFieldName(input.SObjectName) {
-		return nil, errors.New("invalid sobject name")
-	}
-	if input.SObjectID == "" {
-		return nil, errors.New("sobject id is required")
-	}
-	for _, f := range input.Fields {
-		if isInvalidFieldName(f) {
-			return nil, errors.New("invalid field list")
-		}
+	if err := input.validate(); err != nil {
+		return nil, err
 	}
 
-	query := ""
-	if len(input.Fields) > 0 {
-		query = "fields=" + strings.Join(input.Fields, ",")
-	}
 	var sobj SObject
 	req := c.newRequest(&request.Operation{
 		Method:   http.MethodGet,
-		RawQuery: query,
-		APIPath: path.Join(fmt.Sprintf(sObjectPath, c.sess.APIVersion),
-			input.SObjectName, input.SObjectID),
+		RawQuery: FieldsQuery(input.Fields),
+		APIPath:  c.sObjectPath(input.SObjectName, input.SObjectID),
 	}, request.JSONResult, &sobj, http.StatusOK)
 
 	return &GetSObjectOutput{sobj}, req.Send()
 }
 
+func (input *GetSObjectInput) validate() error {
+	if isInvalidFieldName(input.SObjectName) {
+		return errors.New("invalid sobject name")
+	}
+	if input.SObjectID == "" {
+		return errors.New("sobject id is required")
+	}
+	return ValidateFields(input.Fields)
+}
+
 // GetSObjectByExternalIDInput stores the input for retrieving a SObject by external ID.
 type GetSObjectByExternalIDInput struct {
 	SObjectName     string
@@ -112,35 +116,32 @@ type GetSObjectByExternalIDOutput struct {
 // GetSObjectByExternalID retrieves the SObject from the Salesforce API using the external Id.
 func (c *Client) GetSObjectByExternalID(input *GetSObjectByExternalIDInput) (*GetSObjectByExternalIDOutput, error) {
 	// validate parameters
-	if isInvalidFieldName(input.SObjectName) {
-		return nil, errors.New("invalid sobject name")
-	}
-	if isInvalidFieldName(input.ExternalIDField) {
-		return nil, errors.New("invalid external id field")
-	}
-	if input.ExternalID == "" {
-		return nil, errors.New("external id is required")
-	}
-	for _, f := range input.Fields {
-		if isInvalidFieldName(f) {
-			return nil, errors.New("invalid field list")
-		}
+	if err := input.validate(); err != nil {
+		return nil, err
 	}
 
-	query := ""
-	if len(input.Fields) > 0 {
-		query = "fields=" + strings.Join(input.Fields, ",")
-	}
 	var sobj SObject
 	req := c.newRequest(&request.Operation{
 		Method:   http.MethodGet,
-		RawQuery: query,
-		APIPath: path.Join(fmt.Sprintf(sObjectPath, c.sess.APIVersion),
-			input.SObjectName, input.ExternalIDField, input.ExternalID),
+		RawQuery: FieldsQuery(input.Fields),
+		APIPath:  c.sObjectPath(input.SObjectName, input.ExternalIDField, input.ExternalID),
 	}, request.JSONResult, &sobj, http.StatusOK)
 	return &GetSObjectByExternalIDOutput{sobj}, req.Send()
 }
 
+func (input *GetSObjectByExternalIDInput) validate() error {
+	if isInvalidFieldName(input.SObjectName) {
+		return errors.New("invalid sobject name")
+	}
+	if isInvalidFieldName(input.ExternalIDField) {
+		return errors.New("invalid external id field")
+	}
+	if input.ExternalID == "" {
+		return errors.New("external id is required")
+	}
+	return ValidateFields(input.Fields)
+}
+
 // UpdateSObjectInput stores the input for updating a SObject by ID.
 type UpdateSObjectInput struct {
 	SObjectName string
@@ -155,14 +156,8 @@ type UpdateSObjectOutput struct {
 // UpdateSObject updates the SObject using the Salesforce API.
 func (c *Client) UpdateSObject(input *UpdateSObjectInput) (*UpdateSObjectOutput, error) {
 	// validate parameters
-	if isInvalidFieldName(input.SObjectName) {
-		return nil, errors.New("invalid sobject name")
-	}
-	if input.SObjectID == "" {
-		return nil, errors.New("sobject id is required")
-	}
-	if len(input.SObject) == 0 {
-		return nil, errors.New("sobject value is required")
+	if err := input.validate(); err != nil {
+		return nil, err
 	}
 
 	buf := &bytes.Buffer{}
@@ -170,14 +165,26 @@ func (c *Client) UpdateSObject(input *UpdateSObjectInput) (*UpdateSObjectOutput,
 		return nil, fmt.Errorf("couldn't marshal sobject: %v", err)
 	}
 	req := c.newRequest(&request.Operation{
-		Method: http.MethodPatch,
-		APIPath: path.Join(fmt.Sprintf(sObjectPath, c.sess.APIVersion),
-			input.SObjectName, input.SObjectID),
-		Body: buf,
+		Method:  http.MethodPatch,
+		APIPath: c.sObjectPath(input.SObjectName, input.SObjectID),
+		Body:    buf,
 	}, request.JSONResult, nil, http.StatusNoContent)
 	return &UpdateSObjectOutput{}, req.Send()
 }
 
+func (input *UpdateSObjectInput) validate() error {
+	if isInvalidFieldName(input.SObjectName) {
+		return errors.New("invalid sobject name")
+	}
+	if input.SObjectID == "" {
+		return errors.New("sobject id is required")
+	}
+	if len(input.SObject) == 0 {
+		return errors.New("sobject value is required")
+	}
+	return nil
+}
+
 // UpsertSObjectByExternalIDInput stores the input for upserting a SObject by external ID.
 type UpsertSObjectByExternalIDInput struct {
 	SObjectName     string
@@ -195,17 +202,8 @@ type UpsertSObjectByExternalIDOutput struct {
 // UpsertSObjectByExternalID creates/updates the SObject using the Salesforce API.
 func (c *Client) UpsertSObjectByExternalID(input *UpsertSObjectByExternalIDInput) (*UpsertSObjectByExternalIDOutput, error) {
 	// validate parameters
-	if isInvalidFieldName(input.SObjectName) {
-		return nil, errors.New("invalid sobject name")
-	}
-	if isInvalidFieldName(input.ExternalIDField) {
-		return nil, errors.New("invalid external id field")
-	}
-	if input.ExternalID == "" {
-		return nil, errors.New("external id is required")
-	}
-	if len(input.SObject) == 0 {
-		return nil, errors.New("sobject value is required")
+	if err := input.validate(); err != nil {
+		return nil, err
 	}
 
 	buf := &bytes.Buffer{}
@@ -214,14 +212,29 @@ func (c *Client) UpsertSObjectByExternalID(input *UpsertSObjectByExternalIDInput
 	}
 	var result UpsertResult
 	req := c.newRequest(&request.Operation{
-		Method: http.MethodPatch,
-		APIPath: path.Join(fmt.Sprintf(sObjectPath, c.sess.APIVersion),
-			input.SObjectName, input.ExternalIDField, input.ExternalID),
-		Body: buf,
+		Method:  http.MethodPatch,
+		APIPath: c.sObjectPath(input.SObjectName, input.ExternalIDField, input.ExternalID),
+		Body:    buf,
 	}, request.JSONResult, &result, http.StatusOK, http.StatusCreated)
 	return &UpsertSObjectByExternalIDOutput{&result}, req.Send()
 }
 
+func (input *UpsertSObjectByExternalIDInput) validate() error {
+	if isInvalidFieldName(input.SObjectName) {
+		return errors.New("invalid sobject name")
+	}
+	if isInvalidFieldName(input.ExternalIDField) {
+		return errors.New("invalid external id field")
+	}
+	if input.ExternalID == "" {
+		return errors.New("external id is required")
+	}
+	if len(input.SObject) == 0 {
+		return errors.New("sobject value is required")
+	}
+	return nil
+}
+
 // DeleteSObjectInput stores the input for deleting a SObject.
 type DeleteSObjectInput struct {
 	SObjectName string
@@ -234,23 +247,29 @@ type DeleteSObjectOutput struct{}
 // DeleteSObject deletes the Sobject using the Salesforce API.
 func (c *Client) DeleteSObject(input *DeleteSObjectInput) (*DeleteSObjectOutput, error) {
 	// validate parameters
-	if isInvalidFieldName(input.SObjectName) {
-		return nil, errors.New("invalid sobject name")
-	}
-	if input.SObjectID == "" {
-		return nil, errors.New("sobject id is required")
+	if err := input.validate(); err != nil {
+		return nil, err
 	}
 
 	req := c.newRequest(&request.Operation{
-		Method: http.MethodDelete,
-		APIPath: path.Join(fmt.Sprintf(sObjectPath, c.sess.APIVersion),
-			input.SObjectName, input.SObjectID),
+		Method:  http.MethodDelete,
+		APIPath: c.sObjectPath(input.SObjectName, input.SObjectID),
 	}, request.JSONResult, nil, http.StatusNoContent)
 
 	// do delete
 	return &DeleteSObjectOutput{}, req.Send()
 }
 
+func (input *DeleteSObjectInput) validate() error {
+	if isInvalidFieldName(input.SObjectName) {
+		return errors.New("invalid sobject name")
+	}
+	if input.SObjectID == "" {
+		return errors.New("sobject id is required")
+	}
+	return nil
+}
+
 // QueryInput stores the input for querying SObjects.
 type QueryInput struct {
 	Query string
@@ -263,18 +282,45 @@ type QueryOutput struct {
 
 // Query executes a SOQL query using the Salesforce API.
 func (c *Client) Query(input *QueryInput) (*QueryOutput, error) {
+	// validate parameters
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+
+	queryResult, err := c.query(queryPath, input.Query)
+	return &QueryOutput{queryResult}, err
+}
+
+func (input *QueryInput) validate() error {
+	if input.Query == "" {
+		return errors.New("query string is required")
+	}
+	return nil
+}
+
+// QueryAllRowsInput stores the input for querying SObjects, including deleted and
+// archived records.
+type QueryAllRowsInput struct {
+	Query string
+}
+
+// QueryAllRowsOutput stores the output after querying SObjects, including deleted and
+// archived records.
+type QueryAllRowsOutput struct {
+	Result *QueryResult
+}
+
+// QueryAllRows executes a SOQL query using the Salesforce API. Unlike Query, the result
+// includes deleted records and archived activities. The remaining records are retrieved
+// using QueryMore.
+func (c *Client) QueryAllRows(input *QueryAllRowsInput) (*QueryAllRowsOutput, error) {
 	// validate parameters
 	if input.Query == "" {
 		return nil, errors.New("query string is required")
 	}
 
-	var queryResult QueryResult
-	req := c.newRequest(&request.Operation{
-		Method:   http.MethodGet,
-		APIPath:  fmt.Sprintf(queryPath, c.sess.APIVersion),
-		RawQuery: fmt.Sprintf("q=%s", url.QueryEscape(input.Query)),
-	}, request.JSONResult, &queryResult, http.StatusOK)
-	return &QueryOutput{&queryResult}, req.Send()
+	queryResult, err := c.query(queryAllPath, input.Query)
+	return &QueryAllRowsOutput{queryResult}, err
 }
 
 // QueryMoreInput stores the input for querying the next batch of records.
@@ -302,6 +348,40 @@ func (c *Client) QueryMore(input *QueryMoreInput) (*QueryMoreOutput, error) {
 	return &QueryMoreOutput{&queryResult}, req.Send()
 }
 
+// sObjectPath returns the api path for the sobject resource joined with the elements.
+func (c *Client) sObjectPath(elem ...string) string {
+	return path.Join(append([]string{fmt.Sprintf(sObjectPath, c.sess.APIVersion)}, elem...)...)
+}
+
+// FieldsQuery returns the raw query for the field list (e.g. fields=Id,Name).
+func FieldsQuery(fields []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	return "fields=" + strings.Join(fields, ",")
+}
+
+// ValidateFields returns an error if a field in the list is not a valid field name.
+func ValidateFields(fields []string) error {
+	for _, f := range fields {
+		if isInvalidFieldName(f) {
+			return errors.New("invalid field list")
+		}
+	}
+	return nil
+}
+
+// query executes the SOQL query using the query resource at the api path.
+func (c *Client) query(apiPath, query string) (*QueryResult, error) {
+	var queryResult QueryResult
+	req := c.newRequest(&request.Operation{
+		Method:   http.MethodGet,
+		APIPath:  fmt.Sprintf(apiPath, c.sess.APIVersion),
+		RawQuery: fmt.Sprintf("q=%s", url.QueryEscape(query)),
+	}, request.JSONResult, &queryResult, http.StatusOK)
+	return &queryResult, req.Send()
+}
+
 func (c *Client) newRequest(op *request.Operation, resultType request.ResultType,
 	result interface{}, statusCodes ...int) *request.Request {
 	return request.New(c.sess, op,
@@ -0,0 +1,213 @@
+package restapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+// GetSObjectBlobInput stores the input for retrieving the blob field (e.g.
+// ContentVersion.VersionData, Attachment.Body) of a SObject.
+type GetSObjectBlobInput struct {
+	SObjectName string
+	SObjectID   string
+	BlobField   string
+}
+
+// GetSObjectBlobOutput stores the output after retrieving the blob field of a SObject. The
+// caller must close the body.
+type GetSObjectBlobOutput struct {
+	Body io.ReadCloser
+}
+
+// GetSObjectBlob retrieves the blob field of the SObject from the Salesforce API. The blob is
+// streamed instead of read into memory.
+func (c *Client) GetSObjectBlob(input *GetSObjectBlobInput) (*GetSObjectBlobOutput, error) {
+	// validate parameters
+	if isInvalidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+	if input.SObjectID == "" {
+		return nil, errors.New("sobject id is required")
+	}
+	if isInvalidFieldName(input.BlobField) {
+		return nil, errors.New("invalid blob field")
+	}
+
+	var body io.ReadCloser
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodGet,
+		APIPath: c.sObjectPath(input.SObjectName, input.SObjectID, input.BlobField),
+	}, request.StreamResult, &body, http.StatusOK)
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+	return &GetSObjectBlobOutput{body}, nil
+}
+
+// CreateSObjectWithBlobInput stores the input for creating a SObject with a blob field. The
+// blob is sent as the blob field with the file name.
+type CreateSObjectWithBlobInput struct {
+	SObjectName string
+	SObject     SObject
+	BlobField   string
+	FileName    string
+	Blob        io.Reader
+}
+
+// CreateSObjectWithBlobOutput stores the output after creating a SObject with a blob field.
+type CreateSObjectWithBlobOutput struct {
+	Result *UpsertResult
+}
+
+// CreateSObjectWithBlob creates the SObject and uploads the blob in a single multipart
+// request to the Salesforce API. The blob is streamed instead of read into memory, so the
+// request is not retried if the session expired.
+func (c *Client) CreateSObjectWithBlob(input *CreateSObjectWithBlobInput) (*CreateSObjectWithBlobOutput, error) {
+	// validate parameters
+	if isInvalidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+	if len(input.SObject) == 0 {
+		return nil, errors.New("sobject value is required")
+	}
+	if err := validateBlob(input.BlobField, input.FileName, input.Blob); err != nil {
+		return nil, err
+	}
+
+	body, contentType := multipartBody(input.SObjectName, input.SObject, input.BlobField,
+		input.FileName, input.Blob)
+	defer closeMultipartBody(body)
+	var result UpsertResult
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPost,
+		APIPath: c.sObjectPath(input.SObjectName),
+		Header:  http.Header{"Content-Type": {contentType}},
+		Body:    body,
+	}, request.JSONResult, &result, http.StatusCreated)
+	return &CreateSObjectWithBlobOutput{&result}, req.Send()
+}
+
+// UpdateSObjectWithBlobInput stores the input for updating a SObject with a blob field. The
+// SObject contains the other fields to update and can be empty.
+type UpdateSObjectWithBlobInput struct {
+	SObjectName string
+	SObjectID   string
+	SObject     SObject
+	BlobField   string
+	FileName    string
+	Blob        io.Reader
+}
+
+// UpdateSObjectWithBlobOutput stores the output after updating a SObject with a blob field.
+type UpdateSObjectWithBlobOutput struct {
+}
+
+// UpdateSObjectWithBlob updates the SObject and uploads the blob in a single multipart
+// request to the Salesforce API. The blob is streamed instead of read into memory, so the
+// request is not retried if the session expired.
+func (c *Client) UpdateSObjectWithBlob(input *UpdateSObjectWithBlobInput) (*UpdateSObjectWithBlobOutput, error) {
+	// validate parameters
+	if isInvalidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+	if input.SObjectID == "" {
+		return nil, errors.New("sobject id is required")
+	}
+	if err := validateBlob(input.BlobField, input.FileName, input.Blob); err != nil {
+		return nil, err
+	}
+
+	sobj := input.SObject
+	if sobj == nil {
+		sobj = SObject{}
+	}
+	body, contentType := multipartBody(input.SObjectName, sobj, input.BlobField,
+		input.FileName, input.Blob)
+	defer closeMultipartBody(body)
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPatch,
+		APIPath: c.sObjectPath(input.SObjectName, input.SObjectID),
+		Header:  http.Header{"Content-Type": {contentType}},
+		Body:    body,
+	}, request.JSONResult, nil, http.StatusNoContent)
+	return &UpdateSObjectWithBlobOutput{}, req.Send()
+}
+
+// validateBlob returns an error if the blob field, file name or blob is invalid.
+func validateBlob(blobField, fileName string, blob io.Reader) error {
+	if isInvalidFieldName(blobField) {
+		return errors.New("invalid blob field")
+	}
+	if fileName == "" {
+		return errors.New("file name is required")
+	}
+	if blob == nil {
+		return errors.New("blob is required")
+	}
+	return nil
+}
+
+// multipartBody returns a reader that streams the multipart/form-data body containing the
+// SObject as the json entity part and the blob as the binary part, and the content type of
+// the body. The body must be closed with closeMultipartBody once the request is sent.
+func multipartBody(sobjectName string, sobj SObject, blobField, fileName string,
+	blob io.Reader) (*io.PipeReader, string) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipart(mw, sobjectName, sobj, blobField, fileName, blob))
+	}()
+	return pr, mw.FormDataContentType()
+}
+
+// closeMultipartBody closes the multipart body, so that the multipart writer doesn't block
+// forever if the request failed before the body was read.
+func closeMultipartBody(body *io.PipeReader) {
+	_ = body.CloseWithError(errors.New("request finished before the body was read"))
+}
+
+// writeMultipart writes the entity and binary parts to the multipart writer.
+func writeMultipart(mw *multipart.Writer, sobjectName string, sobj SObject, blobField,
+	fileName string, blob io.Reader) error {
+	// entity part
+	entity, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="%s"`, multipartEntityName(sobjectName))},
+		"Content-Type":        {"application/json"},
+	})
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(entity).Encode(sobj); err != nil {
+		return fmt.Errorf("couldn't marshal sobject: %v", err)
+	}
+
+	// binary part
+	binary, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="%s"; filename="%s"`, blobField,
+			strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(fileName))},
+		"Content-Type": {"application/octet-stream"},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(binary, blob); err != nil {
+		return fmt.Errorf("couldn't read blob: %v", err)
+	}
+	return mw.Close()
+}
+
+// multipartEntityName returns the name of the json entity part for the SObject.
+func multipartEntityName(sobjectName string) string {
+	if strings.EqualFold(sobjectName, "ContentVersion") {
+		return "entity_content"
+	}
+	return "entity_" + strings.ToLower(sobjectName)
+}
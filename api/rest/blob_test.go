@@ -0,0 +1,217 @@
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+// multipartValidator validates the entity and binary parts of a multipart request.
+type multipartValidator struct {
+	entityName string
+	entity     SObject
+	blobField  string
+	fileName   string
+	blob       string
+}
+
+// Validate implements the RequestValidator interface.
+func (v *multipartValidator) Validate(r *http.Request) error {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return fmt.Errorf("multipartValidator failed: invalid content type %q", r.Header.Get("Content-Type"))
+	}
+	mr := multipart.NewReader(r.Body, params["boundary"])
+
+	// entity part
+	part, err := mr.NextPart()
+	if err != nil {
+		return fmt.Errorf("multipartValidator failed: missing entity part: %v", err)
+	}
+	var entity SObject
+	if err := json.NewDecoder(part).Decode(&entity); err != nil {
+		return fmt.Errorf("multipartValidator failed: invalid entity part: %v", err)
+	}
+	if part.FormName() != v.entityName || part.Header.Get("Content-Type") != "application/json" ||
+		fmt.Sprint(entity) != fmt.Sprint(v.entity) {
+		return fmt.Errorf("multipartValidator failed: unexpected entity part %q: %v", part.FormName(), entity)
+	}
+
+	// binary part
+	part, err = mr.NextPart()
+	if err != nil {
+		return fmt.Errorf("multipartValidator failed: missing binary part: %v", err)
+	}
+	b, err := ioutil.ReadAll(part)
+	if err != nil {
+		return fmt.Errorf("multipartValidator failed: invalid binary part: %v", err)
+	}
+	if part.FormName() != v.blobField || part.FileName() != v.fileName || string(b) != v.blob {
+		return fmt.Errorf("multipartValidator failed: unexpected binary part %q (%q): %q",
+			part.FormName(), part.FileName(), b)
+	}
+	return nil
+}
+
+func TestGetSObjectBlob(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		objectType   string
+		objectID     string
+		blobField    string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", "069", "VersionData", 0, 0, "invalid sobject name"},
+		{"ContentVersion", "", "VersionData", 0, 0, "sobject id is required"},
+		{"ContentVersion", "069", "", 0, 0, "invalid blob field"},
+		{"ContentVersion", "069", "VersionData", 200, 1, ""},
+		{"ContentVersion", "069", "VersionData", 404, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/sobjects/%s/%s/%s", apiVersion, test.objectType,
+			test.objectID, test.blobField)
+		validators := []testserver.RequestValidator{authTokenValidator, emptyQueryValidator,
+			emptyBodyValidator, &testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.GetSObjectBlob(&GetSObjectBlobInput{
+				SObjectName: test.objectType,
+				SObjectID:   test.objectID,
+				BlobField:   test.blobField,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*GetSObjectBlobOutput)
+			if !assert.True(t, ok, assertMsg) {
+				return
+			}
+			b, err := ioutil.ReadAll(out.Body)
+			assert.Nil(t, err, assertMsg)
+			assert.Nil(t, out.Body.Close(), assertMsg)
+			assert.Contains(t, string(b), "binary data", assertMsg)
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: "binary data"}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestCreateSObjectWithBlob(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	sobj := SObject{"Title": "Report", "PathOnClient": "report.pdf"}
+	tests := []struct {
+		objectType   string
+		sobj         SObject
+		blobField    string
+		fileName     string
+		blob         string
+		entityName   string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", sobj, "VersionData", "report.pdf", "data", "", 0, 0, "invalid sobject name"},
+		{"ContentVersion", nil, "VersionData", "report.pdf", "data", "", 0, 0, "sobject value is required"},
+		{"ContentVersion", sobj, "", "report.pdf", "data", "", 0, 0, "invalid blob field"},
+		{"ContentVersion", sobj, "VersionData", "", "data", "", 0, 0, "file name is required"},
+		{"ContentVersion", sobj, "VersionData", "report.pdf", "%PDF-1.4 data", "entity_content", 201, 1, ""},
+		{"Document", sobj, "Body", `my "report".pdf`, "data", "entity_document", 201, 1, ""},
+		{"ContentVersion", sobj, "VersionData", "report.pdf", "data", "entity_content", 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/sobjects/%s", apiVersion, test.objectType)
+		validators := []testserver.RequestValidator{authTokenValidator, emptyQueryValidator,
+			&testserver.PathValidator{Path: path}, postMethodValidator,
+			&multipartValidator{test.entityName, sobj, test.blobField, test.fileName, test.blob}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.CreateSObjectWithBlob(&CreateSObjectWithBlobInput{
+				SObjectName: test.objectType,
+				SObject:     test.sobj,
+				BlobField:   test.blobField,
+				FileName:    test.fileName,
+				Blob:        strings.NewReader(test.blob),
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*CreateSObjectWithBlobOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, &UpsertResult{ID: "069", Success: true}, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode,
+			Body: UpsertResult{ID: "069", Success: true}}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestUpdateSObjectWithBlob(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		objectID     string
+		sobj         SObject
+		blob         *strings.Reader
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", nil, strings.NewReader("data"), 0, 0, "sobject id is required"},
+		{"00P", nil, nil, 0, 0, "blob is required"},
+		{"00P", nil, strings.NewReader("data"), 204, 1, ""},
+		{"00P", SObject{"Name": "notes.txt"}, strings.NewReader("data"), 204, 1, ""},
+		{"00P", nil, strings.NewReader("data"), 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/sobjects/Attachment/%s", apiVersion, test.objectID)
+		entity := test.sobj
+		if entity == nil {
+			entity = SObject{}
+		}
+		validators := []testserver.RequestValidator{authTokenValidator, emptyQueryValidator,
+			&testserver.PathValidator{Path: path}, patchMethodValidator,
+			&multipartValidator{"entity_attachment", entity, "Body", "notes.txt", "data"}}
+
+		requestFunc := func() (interface{}, error) {
+			input := &UpdateSObjectWithBlobInput{
+				SObjectName: "Attachment",
+				SObjectID:   test.objectID,
+				SObject:     test.sobj,
+				BlobField:   "Body",
+				FileName:    "notes.txt",
+			}
+			if test.blob != nil {
+				input.Blob = test.blob
+			}
+			return client.UpdateSObjectWithBlob(input)
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, nil,
+			test.requestCount, validators, handler)
+	}
+}
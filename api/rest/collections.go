@@ -0,0 +1,331 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+const (
+	compositeSObjectsPath = "/services/data/%s/composite/sobjects/"
+
+	// maxCollectionRecords is the maximum number of records saved or deleted in a single
+	// sObject collection request.
+	maxCollectionRecords = 200
+
+	// maxCollectionRetrieveIDs is the maximum number of records retrieved in a single
+	// sObject collection request.
+	maxCollectionRetrieveIDs = 2000
+)
+
+// CreateSObjectsInput stores the input for creating a list of SObjects. If AllOrNone is
+// true, no record is created when a record fails. Lists larger than 200 records are split
+// into multiple requests, and AllOrNone only applies within each request.
+type CreateSObjectsInput struct {
+	SObjectName string
+	SObjects    []SObject
+	AllOrNone   bool
+}
+
+// CreateSObjectsOutput stores the output after creating a list of SObjects. The results are
+// in the same order as the input SObjects.
+type CreateSObjectsOutput struct {
+	Results []*SaveResult
+}
+
+// CreateSObjects creates the SObjects using the sObject Collections resource of the
+// Salesforce API.
+func (c *Client) CreateSObjects(input *CreateSObjectsInput) (*CreateSObjectsOutput, error) {
+	// validate parameters
+	if isInvalidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+	if err := validateCollection(input.SObjects, ""); err != nil {
+		return nil, err
+	}
+
+	results, err := c.saveSObjects(http.MethodPost, c.compositeSObjectsPath(),
+		input.SObjectName, input.SObjects, input.AllOrNone)
+	return &CreateSObjectsOutput{results}, err
+}
+
+// UpdateSObjectsInput stores the input for updating a list of SObjects. Each SObject must
+// contain the Id field. If AllOrNone is true, no record is updated when a record fails.
+// Lists larger than 200 records are split into multiple requests, and AllOrNone only
+// applies within each request.
+type UpdateSObjectsInput struct {
+	SObjectName string
+	SObjects    []SObject
+	AllOrNone   bool
+}
+
+// UpdateSObjectsOutput stores the output after updating a list of SObjects. The results are
+// in the same order as the input SObjects.
+type UpdateSObjectsOutput struct {
+	Results []*SaveResult
+}
+
+// UpdateSObjects updates the SObjects using the sObject Collections resource of the
+// Salesforce API.
+func (c *Client) UpdateSObjects(input *UpdateSObjectsInput) (*UpdateSObjectsOutput, error) {
+	// validate parameters
+	if isInvalidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+	if err := validateCollection(input.SObjects, "Id"); err != nil {
+		return nil, err
+	}
+
+	results, err := c.saveSObjects(http.MethodPatch, c.compositeSObjectsPath(),
+		input.SObjectName, input.SObjects, input.AllOrNone)
+	return &UpdateSObjectsOutput{results}, err
+}
+
+// UpsertSObjectsInput stores the input for upserting a list of SObjects by external ID. Each
+// SObject must contain the external id field. If AllOrNone is true, no record is saved when
+// a record fails. Lists larger than 200 records are split into multiple requests, and
+// AllOrNone only applies within each request.
+type UpsertSObjectsInput struct {
+	SObjectName     string
+	ExternalIDField string
+	SObjects        []SObject
+	AllOrNone       bool
+}
+
+// UpsertSObjectsOutput stores the output after upserting a list of SObjects. The results are
+// in the same order as the input SObjects.
+type UpsertSObjectsOutput struct {
+	Results []*SaveResult
+}
+
+// UpsertSObjects creates/updates the SObjects by external ID using the sObject Collections
+// resource of the Salesforce API.
+func (c *Client) UpsertSObjects(input *UpsertSObjectsInput) (*UpsertSObjectsOutput, error) {
+	// validate parameters
+	if isInvalidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+	if isInvalidFieldName(input.ExternalIDField) {
+		return nil, errors.New("invalid external id field")
+	}
+	if err := validateCollection(input.SObjects, input.ExternalIDField); err != nil {
+		return nil, err
+	}
+
+	results, err := c.saveSObjects(http.MethodPatch,
+		c.compositeSObjectsPath(input.SObjectName, input.ExternalIDField),
+		input.SObjectName, input.SObjects, input.AllOrNone)
+	return &UpsertSObjectsOutput{results}, err
+}
+
+// DeleteSObjectsInput stores the input for deleting a list of SObjects by ID. If AllOrNone
+// is true, no record is deleted when a record fails. Lists larger than 200 ids are split
+// into multiple requests, and AllOrNone only applies within each request.
+type DeleteSObjectsInput struct {
+	SObjectIDs []string
+	AllOrNone  bool
+}
+
+// DeleteSObjectsOutput stores the output after deleting a list of SObjects. The results are
+// in the same order as the input ids.
+type DeleteSObjectsOutput struct {
+	Results []*SaveResult
+}
+
+// DeleteSObjects deletes the SObjects using the sObject Collections resource of the
+// Salesforce API.
+func (c *Client) DeleteSObjects(input *DeleteSObjectsInput) (*DeleteSObjectsOutput, error) {
+	// validate parameters
+	if err := validateIDs(input.SObjectIDs); err != nil {
+		return nil, err
+	}
+
+	results := make([]*SaveResult, 0, len(input.SObjectIDs))
+	err := forEachChunk(len(input.SObjectIDs), maxCollectionRecords, func(start, end int) error {
+		var chunk []*SaveResult
+		req := c.newRequest(&request.Operation{
+			Method:  http.MethodDelete,
+			APIPath: c.compositeSObjectsPath(),
+			RawQuery: fmt.Sprintf("ids=%s&allOrNone=%s",
+				strings.Join(input.SObjectIDs[start:end], ","), strconv.FormatBool(input.AllOrNone)),
+		}, request.JSONResult, &chunk, http.StatusOK)
+		if err := req.Send(); err != nil {
+			return err
+		}
+		if len(chunk) != end-start {
+			return fmt.Errorf("expected %d results, got %d", end-start, len(chunk))
+		}
+		results = append(results, chunk...)
+		return nil
+	})
+	return &DeleteSObjectsOutput{results}, err
+}
+
+// GetSObjectsInput stores the input for retrieving a list of SObjects by ID. At least one
+// field is required. Lists larger than 2000 ids are split into multiple requests.
+type GetSObjectsInput struct {
+	SObjectName string
+	SObjectIDs  []string
+	Fields      []string
+}
+
+// GetSObjectsOutput stores the output after retrieving a list of SObjects. The SObjects are
+// in the same order as the input ids. The SObject is nil if the record was not found.
+type GetSObjectsOutput struct {
+	SObjects []SObject
+}
+
+// GetSObjects retrieves the SObjects using the sObject Collections resource of the
+// Salesforce API.
+func (c *Client) GetSObjects(input *GetSObjectsInput) (*GetSObjectsOutput, error) {
+	// validate parameters
+	if isInvalidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+	if err := validateIDs(input.SObjectIDs); err != nil {
+		return nil, err
+	}
+	if len(input.Fields) == 0 {
+		return nil, errors.New("fields are required")
+	}
+	if err := ValidateFields(input.Fields); err != nil {
+		return nil, err
+	}
+
+	sobjs := make([]SObject, 0, len(input.SObjectIDs))
+	err := forEachChunk(len(input.SObjectIDs), maxCollectionRetrieveIDs, func(start, end int) error {
+		buf := &bytes.Buffer{}
+		if err := json.NewEncoder(buf).Encode(map[string]interface{}{
+			"ids":    input.SObjectIDs[start:end],
+			"fields": input.Fields,
+		}); err != nil {
+			return fmt.Errorf("couldn't marshal sobject ids: %v", err)
+		}
+		var chunk []SObject
+		req := c.newRequest(&request.Operation{
+			Method:  http.MethodPost,
+			APIPath: c.compositeSObjectsPath(input.SObjectName),
+			Body:    buf,
+		}, request.JSONResult, &chunk, http.StatusOK)
+		if err := req.Send(); err != nil {
+			return err
+		}
+		if len(chunk) != end-start {
+			return fmt.Errorf("expected %d sobjects, got %d", end-start, len(chunk))
+		}
+		sobjs = append(sobjs, chunk...)
+		return nil
+	})
+	return &GetSObjectsOutput{sobjs}, err
+}
+
+// saveSObjects sends the records in chunks of 200 to the sObject collection resource at the
+// api path and returns the results in record order. On error, the results of the
+// successful chunks are returned.
+func (c *Client) saveSObjects(method, apiPath, sobjectName string, sobjs []SObject,
+	allOrNone bool) ([]*SaveResult, error) {
+	results := make([]*SaveResult, 0, len(sobjs))
+	err := forEachChunk(len(sobjs), maxCollectionRecords, func(start, end int) error {
+		records := make([]SObject, 0, end-start)
+		for _, sobj := range sobjs[start:end] {
+			records = append(records, withTypeAttribute(sobjectName, sobj))
+		}
+		buf := &bytes.Buffer{}
+		if err := json.NewEncoder(buf).Encode(map[string]interface{}{
+			"allOrNone": allOrNone,
+			"records":   records,
+		}); err != nil {
+			return fmt.Errorf("couldn't marshal sobjects: %v", err)
+		}
+		var chunk []*SaveResult
+		req := c.newRequest(&request.Operation{
+			Method:  method,
+			APIPath: apiPath,
+			Body:    buf,
+		}, request.JSONResult, &chunk, http.StatusOK)
+		if err := req.Send(); err != nil {
+			return err
+		}
+		if len(chunk) != end-start {
+			return fmt.Errorf("expected %d results, got %d", end-start, len(chunk))
+		}
+		results = append(results, chunk...)
+		return nil
+	})
+	return results, err
+}
+
+// compositeSObjectsPath returns the api path for the sObject collections resource joined
+// with the elements.
+func (c *Client) compositeSObjectsPath(elem ...string) string {
+	return path.Join(append([]string{fmt.Sprintf(compositeSObjectsPath, c.sess.APIVersion)}, elem...)...)
+}
+
+// withTypeAttribute returns a copy of the SObject with the type attribute set to the sobject
+// name. The SObject is not modified.
+func withTypeAttribute(sobjectName string, sobj SObject) SObject {
+	attrs := sObjectAttributes(sobj)
+	attrs["type"] = sobjectName
+
+	rec := make(SObject, len(sobj)+1)
+	for k, v := range sobj {
+		rec[k] = v
+	}
+	rec["attributes"] = attrs
+	return rec
+}
+
+// validateCollection returns an error if the list of SObjects is empty, or a SObject is empty
+// or missing the required field.
+func validateCollection(sobjs []SObject, requiredField string) error {
+	if len(sobjs) == 0 {
+		return errors.New("sobjects are required")
+	}
+	for _, sobj := range sobjs {
+		if len(sobj) == 0 {
+			return errors.New("sobject value is required")
+		}
+		if requiredField == "" {
+			continue
+		}
+		if v, ok := sobj[requiredField]; !ok || v == nil || v == "" {
+			return fmt.Errorf("sobject field %q is required", requiredField)
+		}
+	}
+	return nil
+}
+
+// validateIDs returns an error if the list of ids is empty or contains an empty id.
+func validateIDs(ids []string) error {
+	if len(ids) == 0 {
+		return errors.New("sobject ids are required")
+	}
+	for _, id := range ids {
+		if id == "" {
+			return errors.New("sobject id is required")
+		}
+	}
+	return nil
+}
+
+// forEachChunk calls the function with the bounds of each chunk of the size, stopping at the
+// first error.
+func forEachChunk(n, size int, fn func(start, end int) error) error {
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+		if err := fn(start, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/Laugusti/go-sforce/sforce/sforceerr"
+	"github.com/stretchr/testify/assert"
+)
+
+var saveResults = []*SaveResult{
+	{ID: "001", Success: true, Errors: []*SaveError{}},
+	{Success: false, Errors: []*SaveError{{StatusCode: "REQUIRED_FIELD_MISSING",
+		Message: "Required fields are missing", Fields: []string{"Name"}}}},
+}
+
+func TestCreateSObjects(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	sobjs := []SObject{{"Name": "Acme"}, {"Phone": "555"}}
+	tests := []struct {
+		objectType   string
+		sobjs        []SObject
+		allOrNone    bool
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", sobjs, false, 0, 0, "invalid sobject name"},
+		{"Account", nil, false, 0, 0, "sobjects are required"},
+		{"Account", []SObject{{}}, false, 0, 0, "sobject value is required"},
+		{"Account", sobjs, true, 200, 1, ""},
+		{"Account", sobjs, false, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/composite/sobjects", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.PathValidator{Path: path}, postMethodValidator,
+			&testserver.JSONBodyValidator{Body: map[string]interface{}{
+				"allOrNone": test.allOrNone,
+				"records": []SObject{
+					{"attributes": map[string]interface{}{"type": "Account"}, "Name": "Acme"},
+					{"attributes": map[string]interface{}{"type": "Account"}, "Phone": "555"},
+				},
+			}}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.CreateSObjects(&CreateSObjectsInput{
+				SObjectName: test.objectType,
+				SObjects:    test.sobjs,
+				AllOrNone:   test.allOrNone,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*CreateSObjectsOutput)
+			if !assert.True(t, ok, assertMsg) || !assert.Len(t, out.Results, 2, assertMsg) {
+				return
+			}
+			assert.Equal(t, "001", out.Results[0].ID, assertMsg)
+			assert.Nil(t, out.Results[0].Err(), assertMsg)
+			errs, ok := out.Results[1].Err().(sforceerr.APIErrors)
+			if assert.True(t, ok, assertMsg) && assert.Len(t, errs, 1, assertMsg) {
+				assert.Equal(t, "REQUIRED_FIELD_MISSING", errs[0].ErrorCode, assertMsg)
+			}
+			// input not modified
+			assert.Equal(t, []SObject{{"Name": "Acme"}, {"Phone": "555"}}, sobjs, assertMsg)
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: saveResults}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestUpdateSObjects(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	sobjs := []SObject{{"Id": "001", "Name": "Acme"}, {"Id": "002", "Name": ""}}
+	tests := []struct {
+		objectType   string
+		sobjs        []SObject
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"Account", nil, 0, 0, "sobjects are required"},
+		{"Account", []SObject{{"Name": "Acme"}}, 0, 0, `sobject field "Id" is required`},
+		{"Account", sobjs, 200, 1, ""},
+		{"Account", sobjs, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/composite/sobjects", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.PathValidator{Path: path}, patchMethodValidator,
+			&testserver.JSONBodyValidator{Body: map[string]interface{}{
+				"allOrNone": false,
+				"records": []SObject{
+					{"attributes": map[string]interface{}{"type": "Account"}, "Id": "001", "Name": "Acme"},
+					{"attributes": map[string]interface{}{"type": "Account"}, "Id": "002", "Name": ""},
+				},
+			}}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.UpdateSObjects(&UpdateSObjectsInput{
+				SObjectName: test.objectType,
+				SObjects:    test.sobjs,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*UpdateSObjectsOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, saveResults, out.Results, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: saveResults}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestUpsertSObjects(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	sobjs := []SObject{{"Ext__c": "1", "Name": "Acme",
+		"attributes": map[string]interface{}{"type": "Contact", "referenceId": "ref"}}}
+	tests := []struct {
+		objectType   string
+		extField     string
+		sobjs        []SObject
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"Account", "", sobjs, 0, 0, "invalid external id field"},
+		{"Account", "Ext__c", []SObject{{"Name": "Acme"}}, 0, 0, `sobject field "Ext__c" is required`},
+		{"Account", "Ext__c", sobjs, 200, 1, ""},
+		{"Account", "Ext__c", sobjs, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/composite/sobjects/Account/Ext__c", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.PathValidator{Path: path}, patchMethodValidator,
+			&testserver.JSONBodyValidator{Body: map[string]interface{}{
+				"allOrNone": true,
+				"records": []SObject{{"Ext__c": "1", "Name": "Acme",
+					"attributes": map[string]interface{}{"type": "Account", "referenceId": "ref"}}},
+			}}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.UpsertSObjects(&UpsertSObjectsInput{
+				SObjectName:     test.objectType,
+				ExternalIDField: test.extField,
+				SObjects:        test.sobjs,
+				AllOrNone:       true,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*UpsertSObjectsOutput)
+			if assert.True(t, ok, assertMsg) && assert.Len(t, out.Results, 1, assertMsg) {
+				assert.True(t, out.Results[0].Created, assertMsg)
+			}
+			// input not modified
+			assert.Equal(t, "Contact", sobjs[0]["attributes"].(map[string]interface{})["type"], assertMsg)
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode,
+			Body: []*SaveResult{{ID: "001", Success: true, Created: true}}}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestDeleteSObjects(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		ids          []string
+		allOrNone    bool
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{nil, false, 0, 0, "sobject ids are required"},
+		{[]string{"001", ""}, false, 0, 0, "sobject id is required"},
+		{[]string{"001", "002"}, true, 200, 1, ""},
+		{[]string{"001", "002"}, false, 200, 1, ""},
+		{[]string{"001", "002"}, false, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/composite/sobjects", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.PathValidator{Path: path}, deleteMethodValidator, emptyBodyValidator,
+			&testserver.QueryValidator{Query: url.Values{
+				"ids":       []string{"001,002"},
+				"allOrNone": []string{fmt.Sprint(test.allOrNone)},
+			}}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.DeleteSObjects(&DeleteSObjectsInput{
+				SObjectIDs: test.ids,
+				AllOrNone:  test.allOrNone,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*DeleteSObjectsOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, saveResults, out.Results, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: saveResults}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestGetSObjects(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		objectType   string
+		ids          []string
+		fields       []string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"Account", nil, []string{"Id"}, 0, 0, "sobject ids are required"},
+		{"Account", []string{"001", "002"}, nil, 0, 0, "fields are required"},
+		{"Account", []string{"001", "002"}, []string{"Id", "1d"}, 0, 0, "invalid field list"},
+		{"Account", []string{"001", "002"}, []string{"Id", "Name"}, 200, 1, ""},
+		{"Account", []string{"001", "002"}, []string{"Id", "Name"}, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/composite/sobjects/Account", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.PathValidator{Path: path}, postMethodValidator,
+			&testserver.JSONBodyValidator{Body: map[string]interface{}{
+				"ids":    test.ids,
+				"fields": test.fields,
+			}}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.GetSObjects(&GetSObjectsInput{
+				SObjectName: test.objectType,
+				SObjectIDs:  test.ids,
+				Fields:      test.fields,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*GetSObjectsOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, []SObject{{"Id": "001", "Name": "Acme"}, nil}, out.SObjects, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode,
+			Body: []SObject{{"Id": "001", "Name": "Acme"}, nil}}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestCollectionChunks(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	results := func(n int) testserver.ResponseHandler {
+		body := make([]*SaveResult, n)
+		for i := range body {
+			body[i] = &SaveResult{ID: fmt.Sprintf("%d", i), Success: true}
+		}
+		return &testserver.JSONResponseHandler{StatusCode: http.StatusOK, Body: body}
+	}
+	errorResponse := &testserver.JSONResponseHandler{
+		StatusCode: http.StatusBadRequest,
+		Body:       []interface{}{genericErr},
+	}
+
+	tests := []struct {
+		records      int
+		responses    []testserver.ResponseHandler
+		requestCount int
+		resultCount  int
+		errSnippet   string
+	}{
+		{200, []testserver.ResponseHandler{results(200)}, 1, 200, ""},
+		{401, []testserver.ResponseHandler{results(200), results(200), results(1)}, 3, 401, ""},
+		{201, []testserver.ResponseHandler{results(200), errorResponse}, 2, 200, "GENERIC_ERROR"},
+		{2, []testserver.ResponseHandler{results(1)}, 1, 0, "expected 2 results, got 1"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: test.responses},
+			authTokenValidator, postMethodValidator)
+		server.RequestCount = 0
+
+		sobjs := make([]SObject, test.records)
+		for i := range sobjs {
+			sobjs[i] = SObject{"Name": fmt.Sprintf("Acme %d", i)}
+		}
+		out, err := client.CreateSObjects(&CreateSObjectsInput{SObjectName: "Account", SObjects: sobjs})
+		assert.Equal(t, test.requestCount, server.RequestCount, assertMsg)
+		if assert.NotNil(t, out, assertMsg) {
+			assert.Len(t, out.Results, test.resultCount, assertMsg)
+		}
+		if test.errSnippet == "" {
+			assert.Nil(t, err, assertMsg)
+		} else if assert.Error(t, err, assertMsg) {
+			assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+	"github.com/Laugusti/go-sforce/sforce/sforceerr"
+)
+
+const (
+	compositePath = "/services/data/%s/composite/"
+
+	// maxCompositeSubrequests is the maximum number of subrequests in a composite request.
+	maxCompositeSubrequests = 25
+)
+
+var referenceIDRE = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// CompositeSubrequest is a request executed as part of a composite request. The body and url
+// of a subrequest can reference the result of a previous subrequest using the
+// @{referenceId.field} syntax (e.g. @{NewAccount.id}).
+type CompositeSubrequest struct {
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	ReferenceID string            `json:"referenceId"`
+	Body        interface{}       `json:"body,omitempty"`
+	HTTPHeaders map[string]string `json:"httpHeaders,omitempty"`
+}
+
+// CompositeSubresponse is the result of a composite subrequest.
+type CompositeSubresponse struct {
+	Body           json.RawMessage   `json:"body"`
+	HTTPHeaders    map[string]string `json:"httpHeaders"`
+	HTTPStatusCode int               `json:"httpStatusCode"`
+	ReferenceID    string            `json:"referenceId"`
+}
+
+// Err returns the error of the subrequest, or nil if the subrequest succeeded. The error is a
+// sforceerr.APIErrors if the response body contains the Salesforce API errors.
+func (r *CompositeSubresponse) Err() error {
+	return subresponseErr(fmt.Sprintf("subrequest %q", r.ReferenceID), r.HTTPStatusCode, r.Body)
+}
+
+// Decode unmarshals the response body of the subrequest into the value.
+func (r *CompositeSubresponse) Decode(v interface{}) error {
+	if len(r.Body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal subresponse %q: %v", r.ReferenceID, err)
+	}
+	return nil
+}
+
+// CompositeInput stores the input for executing a composite request. If AllOrNone is true,
+// all subrequests are rolled back when a subrequest fails.
+type CompositeInput struct {
+	AllOrNone          bool
+	CollateSubrequests bool
+	Subrequests        []*CompositeSubrequest
+}
+
+// CompositeOutput stores the output after executing a composite request. The results are in
+// the same order as the subrequests.
+type CompositeOutput struct {
+	Results []*CompositeSubresponse
+}
+
+// Result returns the result of the subrequest with the reference id, or nil if there is no
+// such subrequest.
+func (o *CompositeOutput) Result(referenceID string) *CompositeSubresponse {
+	for _, r := range o.Results {
+		if r.ReferenceID == referenceID {
+			return r
+		}
+	}
+	return nil
+}
+
+// Composite executes a series of subrequests in a single call to the Salesforce API.
+func (c *Client) Composite(input *CompositeInput) (*CompositeOutput, error) {
+	// validate parameters
+	if len(input.Subrequests) == 0 {
+		return nil, errors.New("subrequests are required")
+	}
+	if len(input.Subrequests) > maxCompositeSubrequests {
+		return nil, fmt.Errorf("too many subrequests (max %d)", maxCompositeSubrequests)
+	}
+	if err := validateSubrequests(input.Subrequests); err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(map[string]interface{}{
+		"allOrNone":          input.AllOrNone,
+		"collateSubrequests": input.CollateSubrequests,
+		"compositeRequest":   input.Subrequests,
+	}); err != nil {
+		return nil, fmt.Errorf("couldn't marshal composite request: %v", err)
+	}
+	var result struct {
+		CompositeResponse []*CompositeSubresponse `json:"compositeResponse"`
+	}
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPost,
+		APIPath: fmt.Sprintf(compositePath, c.sess.APIVersion),
+		Body:    buf,
+	}, request.JSONResult, &result, http.StatusOK)
+	err := req.Send()
+	return &CompositeOutput{result.CompositeResponse}, err
+}
+
+// NewCreateSObjectSubrequest returns a composite subrequest that creates the SObject.
+func (c *Client) NewCreateSObjectSubrequest(referenceID string, input *CreateSObjectInput) (*CompositeSubrequest, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return &CompositeSubrequest{
+		Method:      http.MethodPost,
+		URL:         c.sObjectPath(input.SObjectName),
+		ReferenceID: referenceID,
+		Body:        input.SObject,
+	}, nil
+}
+
+// NewGetSObjectSubrequest returns a composite subrequest that retrieves the SObject.
+func (c *Client) NewGetSObjectSubrequest(referenceID string, input *GetSObjectInput) (*CompositeSubrequest, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return &CompositeSubrequest{
+		Method:      http.MethodGet,
+		URL:         withRawQuery(c.sObjectPath(input.SObjectName, input.SObjectID), FieldsQuery(input.Fields)),
+		ReferenceID: referenceID,
+	}, nil
+}
+
+// NewGetSObjectByExternalIDSubrequest returns a composite subrequest that retrieves the
+// SObject using the external id.
+func (c *Client) NewGetSObjectByExternalIDSubrequest(referenceID string, input *GetSObjectByExternalIDInput) (*CompositeSubrequest, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return &CompositeSubrequest{
+		Method: http.MethodGet,
+		URL: withRawQuery(c.sObjectPath(input.SObjectName, input.ExternalIDField, input.ExternalID),
+			FieldsQuery(input.Fields)),
+		ReferenceID: referenceID,
+	}, nil
+}
+
+// NewUpdateSObjectSubrequest returns a composite subrequest that updates the SObject.
+func (c *Client) NewUpdateSObjectSubrequest(referenceID string, input *UpdateSObjectInput) (*CompositeSubrequest, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return &CompositeSubrequest{
+		Method:      http.MethodPatch,
+		URL:         c.sObjectPath(input.SObjectName, input.SObjectID),
+		ReferenceID: referenceID,
+		Body:        input.SObject,
+	}, nil
+}
+
+// NewUpsertSObjectByExternalIDSubrequest returns a composite subrequest that creates/updates
+// the SObject using the external id.
+func (c *Client) NewUpsertSObjectByExternalIDSubrequest(referenceID string, input *UpsertSObjectByExternalIDInput) (*CompositeSubrequest, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return &CompositeSubrequest{
+		Method:      http.MethodPatch,
+		URL:         c.sObjectPath(input.SObjectName, input.ExternalIDField, input.ExternalID),
+		ReferenceID: referenceID,
+		Body:        input.SObject,
+	}, nil
+}
+
+// NewDeleteSObjectSubrequest returns a composite subrequest that deletes the SObject.
+func (c *Client) NewDeleteSObjectSubrequest(referenceID string, input *DeleteSObjectInput) (*CompositeSubrequest, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return &CompositeSubrequest{
+		Method:      http.MethodDelete,
+		URL:         c.sObjectPath(input.SObjectName, input.SObjectID),
+		ReferenceID: referenceID,
+	}, nil
+}
+
+// NewQuerySubrequest returns a composite subrequest that executes the SOQL query.
+func (c *Client) NewQuerySubrequest(referenceID string, input *QueryInput) (*CompositeSubrequest, error) {
+	if err := input.validate(); err != nil {
+		return nil, err
+	}
+	return &CompositeSubrequest{
+		Method: http.MethodGet,
+		URL: withRawQuery(fmt.Sprintf(queryPath, c.sess.APIVersion),
+			"q="+url.QueryEscape(input.Query)),
+		ReferenceID: referenceID,
+	}, nil
+}
+
+// validateSubrequests returns an error if a subrequest is invalid or reference ids are not
+// unique.
+func validateSubrequests(subrequests []*CompositeSubrequest) error {
+	refIDs := make(map[string]bool)
+	for _, r := range subrequests {
+		if r == nil {
+			return errors.New("subrequest is required")
+		}
+		if r.Method == "" {
+			return errors.New("subrequest method is required")
+		}
+		if r.URL == "" {
+			return errors.New("subrequest url is required")
+		}
+		if !referenceIDRE.MatchString(r.ReferenceID) {
+			return fmt.Errorf("invalid reference id %q", r.ReferenceID)
+		}
+		if refIDs[r.ReferenceID] {
+			return fmt.Errorf("duplicate reference id %q", r.ReferenceID)
+		}
+		refIDs[r.ReferenceID] = true
+	}
+	return nil
+}
+
+// subresponseErr returns the error for the subresponse status code and body, or nil if the
+// status code is not an error.
+func subresponseErr(name string, statusCode int, body []byte) error {
+	if statusCode < http.StatusBadRequest {
+		return nil
+	}
+	var errs sforceerr.APIErrors
+	if err := json.Unmarshal(body, &errs); err != nil || len(errs) == 0 {
+		return fmt.Errorf("%s failed with status code %d: %s", name, statusCode, body)
+	}
+	for _, err := range errs {
+		err.ActualStatusCode = statusCode
+	}
+	return errs
+}
+
+// withRawQuery returns the api path with the raw query.
+func withRawQuery(apiPath, rawQuery string) string {
+	if rawQuery == "" {
+		return apiPath
+	}
+	return apiPath + "?" + rawQuery
+}
@@ -0,0 +1,211 @@
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/Laugusti/go-sforce/sforce/sforceerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposite(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	createAccount := &CompositeSubrequest{Method: "POST",
+		URL:         "/services/data/mock/sobjects/Account",
+		ReferenceID: "NewAccount", Body: SObject{"Name": "Acme"}}
+	createContact := &CompositeSubrequest{Method: "POST",
+		URL:         "/services/data/mock/sobjects/Contact",
+		ReferenceID: "NewContact", Body: SObject{"AccountId": "@{NewAccount.id}"}}
+	tooMany := make([]*CompositeSubrequest, 26)
+	for i := range tooMany {
+		tooMany[i] = &CompositeSubrequest{Method: "GET", URL: "url",
+			ReferenceID: fmt.Sprintf("ref%d", i)}
+	}
+
+	tests := []struct {
+		allOrNone    bool
+		subrequests  []*CompositeSubrequest
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{false, nil, 0, 0, "subrequests are required"},
+		{false, tooMany, 0, 0, "too many subrequests"},
+		{false, []*CompositeSubrequest{nil}, 0, 0, "subrequest is required"},
+		{false, []*CompositeSubrequest{{URL: "url", ReferenceID: "a"}}, 0, 0, "subrequest method is required"},
+		{false, []*CompositeSubrequest{{Method: "GET", ReferenceID: "a"}}, 0, 0, "subrequest url is required"},
+		{false, []*CompositeSubrequest{{Method: "GET", URL: "url", ReferenceID: "1a"}}, 0, 0, "invalid reference id"},
+		{false, []*CompositeSubrequest{createAccount, createAccount}, 0, 0, "duplicate reference id"},
+		{true, []*CompositeSubrequest{createAccount, createContact}, 200, 1, ""},
+		{false, []*CompositeSubrequest{createAccount, createContact}, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/composite", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.PathValidator{Path: path}, postMethodValidator,
+			&testserver.JSONBodyValidator{Body: map[string]interface{}{
+				"allOrNone":          test.allOrNone,
+				"collateSubrequests": false,
+				"compositeRequest":   test.subrequests,
+			}}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.Composite(&CompositeInput{
+				AllOrNone:   test.allOrNone,
+				Subrequests: test.subrequests,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*CompositeOutput)
+			if !assert.True(t, ok, assertMsg) || !assert.Len(t, out.Results, 2, assertMsg) {
+				return
+			}
+			// created account
+			account := out.Result("NewAccount")
+			if assert.NotNil(t, account, assertMsg) {
+				assert.Nil(t, account.Err(), assertMsg)
+				var result UpsertResult
+				assert.Nil(t, account.Decode(&result), assertMsg)
+				assert.Equal(t, "001", result.ID, assertMsg)
+			}
+			// failed contact
+			contact := out.Result("NewContact")
+			if assert.NotNil(t, contact, assertMsg) {
+				errs, ok := contact.Err().(sforceerr.APIErrors)
+				if assert.True(t, ok, assertMsg) && assert.Len(t, errs, 1, assertMsg) {
+					assert.Equal(t, "REQUIRED_FIELD_MISSING", errs[0].ErrorCode, assertMsg)
+					assert.Equal(t, 400, errs[0].ActualStatusCode, assertMsg)
+				}
+			}
+			assert.Nil(t, out.Result("Missing"), assertMsg)
+		}
+		handler := &testserver.JSONResponseHandler{
+			StatusCode: test.statusCode,
+			Body: map[string]interface{}{
+				"compositeResponse": []interface{}{
+					map[string]interface{}{
+						"body":           UpsertResult{ID: "001", Success: true},
+						"httpHeaders":    map[string]string{"Location": "/services/data/mock/sobjects/Account/001"},
+						"httpStatusCode": 201,
+						"referenceId":    "NewAccount",
+					},
+					map[string]interface{}{
+						"body": []sforceerr.APIError{{Message: "Required fields are missing",
+							ErrorCode: "REQUIRED_FIELD_MISSING", Fields: []string{"LastName"}}},
+						"httpHeaders":    map[string]string{},
+						"httpStatusCode": 400,
+						"referenceId":    "NewContact",
+					},
+				},
+			},
+		}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestCompositeSubresponseErr(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		body       string
+		errSnippet string
+	}{
+		{200, `{"id":"001"}`, ""},
+		{204, ``, ""},
+		{404, `[{"errorCode":"NOT_FOUND","message":"not found"}]`, "NOT_FOUND"},
+		{500, `internal error`, "internal error"},
+		{400, `[]`, `subrequest "ref" failed with status code 400`},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		resp := &CompositeSubresponse{
+			Body:           json.RawMessage(test.body),
+			HTTPStatusCode: test.statusCode,
+			ReferenceID:    "ref",
+		}
+		err := resp.Err()
+		if test.errSnippet == "" {
+			assert.Nil(t, err, assertMsg)
+		} else if assert.Error(t, err, assertMsg) {
+			assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+		}
+	}
+}
+
+func TestCompositeSubrequestBuilders(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	sobj := SObject{"Name": "Acme"}
+	tests := []struct {
+		build      func() (*CompositeSubrequest, error)
+		errSnippet string
+		want       *CompositeSubrequest
+	}{
+		{func() (*CompositeSubrequest, error) {
+			return client.NewCreateSObjectSubrequest("ref", &CreateSObjectInput{SObjectName: "Account"})
+		}, "sobject value is required", nil},
+		{func() (*CompositeSubrequest, error) {
+			return client.NewCreateSObjectSubrequest("ref", &CreateSObjectInput{SObjectName: "Account", SObject: sobj})
+		}, "", &CompositeSubrequest{Method: "POST", URL: "/services/data/mock/sobjects/Account",
+			ReferenceID: "ref", Body: sobj}},
+		{func() (*CompositeSubrequest, error) {
+			return client.NewGetSObjectSubrequest("ref", &GetSObjectInput{SObjectName: "Account"})
+		}, "sobject id is required", nil},
+		{func() (*CompositeSubrequest, error) {
+			return client.NewGetSObjectSubrequest("ref", &GetSObjectInput{SObjectName: "Account",
+				SObjectID: "@{acc.id}", Fields: []string{"Id", "Name"}})
+		}, "", &CompositeSubrequest{Method: "GET",
+			URL: "/services/data/mock/sobjects/Account/@{acc.id}?fields=Id,Name", ReferenceID: "ref"}},
+		{func() (*CompositeSubrequest, error) {
+			return client.NewGetSObjectByExternalIDSubrequest("ref", &GetSObjectByExternalIDInput{
+				SObjectName: "Account", ExternalIDField: "Ext__c", ExternalID: "1"})
+		}, "", &CompositeSubrequest{Method: "GET",
+			URL: "/services/data/mock/sobjects/Account/Ext__c/1", ReferenceID: "ref"}},
+		{func() (*CompositeSubrequest, error) {
+			return client.NewUpdateSObjectSubrequest("ref", &UpdateSObjectInput{
+				SObjectName: "Account", SObjectID: "001", SObject: sobj})
+		}, "", &CompositeSubrequest{Method: "PATCH", URL: "/services/data/mock/sobjects/Account/001",
+			ReferenceID: "ref", Body: sobj}},
+		{func() (*CompositeSubrequest, error) {
+			return client.NewUpsertSObjectByExternalIDSubrequest("ref", &UpsertSObjectByExternalIDInput{
+				SObjectName: "Account", ExternalIDField: "Ext__c", SObject: sobj})
+		}, "external id is required", nil},
+		{func() (*CompositeSubrequest, error) {
+			return client.NewUpsertSObjectByExternalIDSubrequest("ref", &UpsertSObjectByExternalIDInput{
+				SObjectName: "Account", ExternalIDField: "Ext__c", ExternalID: "1", SObject: sobj})
+		}, "", &CompositeSubrequest{Method: "PATCH", URL: "/services/data/mock/sobjects/Account/Ext__c/1",
+			ReferenceID: "ref", Body: sobj}},
+		{func() (*CompositeSubrequest, error) {
+			return client.NewDeleteSObjectSubrequest("ref", &DeleteSObjectInput{SObjectName: "Account",
+				SObjectID: "001"})
+		}, "", &CompositeSubrequest{Method: "DELETE", URL: "/services/data/mock/sobjects/Account/001",
+			ReferenceID: "ref"}},
+		{func() (*CompositeSubrequest, error) {
+			return client.NewQuerySubrequest("ref", &QueryInput{})
+		}, "query string is required", nil},
+		{func() (*CompositeSubrequest, error) {
+			return client.NewQuerySubrequest("ref", &QueryInput{Query: "SELECT Id FROM Account"})
+		}, "", &CompositeSubrequest{Method: "GET",
+			URL: "/services/data/mock/query/?q=SELECT+Id+FROM+Account", ReferenceID: "ref"}},
+	}
+
+	for i, test := range tests {
+		assertMsg := fmt.Sprintf("test: %d", i)
+		got, err := test.build()
+		if test.errSnippet == "" {
+			assert.Nil(t, err, assertMsg)
+			assert.Equal(t, test.want, got, assertMsg)
+		} else if assert.Error(t, err, assertMsg) {
+			assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+		}
+	}
+}
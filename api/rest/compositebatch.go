@@ -0,0 +1,128 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+const (
+	compositeBatchPath = "/services/data/%s/composite/batch/"
+
+	// dataPathPrefix is the prefix removed from api paths for batch subrequest urls.
+	dataPathPrefix = "/services/data/"
+)
+
+// CompositeBatchSubrequest is an independent request executed as part of a composite batch
+// request. The url is relative to /services/data (e.g. v42.0/sobjects/Account/001...).
+type CompositeBatchSubrequest struct {
+	Method    string      `json:"method"`
+	URL       string      `json:"url"`
+	RichInput interface{} `json:"richInput,omitempty"`
+}
+
+// BatchSubrequest returns the composite subrequest as a composite batch subrequest. This
+// allows the composite subrequest builders (e.g. NewGetSObjectSubrequest) to be used for
+// batch requests. The reference id is ignored.
+func (r *CompositeSubrequest) BatchSubrequest() *CompositeBatchSubrequest {
+	return &CompositeBatchSubrequest{
+		Method:    r.Method,
+		URL:       strings.TrimPrefix(r.URL, dataPathPrefix),
+		RichInput: r.Body,
+	}
+}
+
+// CompositeBatchSubresponse is the result of a composite batch subrequest.
+type CompositeBatchSubresponse struct {
+	StatusCode int             `json:"statusCode"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// Err returns the error of the subrequest, or nil if the subrequest succeeded. The error is a
+// sforceerr.APIErrors if the result contains the Salesforce API errors.
+func (r *CompositeBatchSubresponse) Err() error {
+	return subresponseErr("batch subrequest", r.StatusCode, r.Result)
+}
+
+// Decode unmarshals the result of the subrequest into the value.
+func (r *CompositeBatchSubresponse) Decode(v interface{}) error {
+	if len(r.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(r.Result, v); err != nil {
+		return fmt.Errorf("failed to unmarshal batch subresponse: %v", err)
+	}
+	return nil
+}
+
+// CompositeBatchInput stores the input for executing a composite batch request. If
+// HaltOnError is true, the remaining subrequests are not executed after a subrequest fails.
+type CompositeBatchInput struct {
+	HaltOnError bool
+	Subrequests []*CompositeBatchSubrequest
+}
+
+// CompositeBatchOutput stores the output after executing a composite batch request. The
+// results are in the same order as the subrequests.
+type CompositeBatchOutput struct {
+	HasErrors bool
+	Results   []*CompositeBatchSubresponse
+}
+
+// Errors returns the errors of the failed subrequests, keyed by subrequest index.
+func (o *CompositeBatchOutput) Errors() map[int]error {
+	errs := make(map[int]error)
+	for i, r := range o.Results {
+		if err := r.Err(); err != nil {
+			errs[i] = err
+		}
+	}
+	return errs
+}
+
+// CompositeBatch executes up to 25 independent subrequests in a single call to the
+// Salesforce API.
+func (c *Client) CompositeBatch(input *CompositeBatchInput) (*CompositeBatchOutput, error) {
+	// validate parameters
+	if len(input.Subrequests) == 0 {
+		return nil, errors.New("subrequests are required")
+	}
+	if len(input.Subrequests) > maxCompositeSubrequests {
+		return nil, fmt.Errorf("too many subrequests (max %d)", maxCompositeSubrequests)
+	}
+	for _, r := range input.Subrequests {
+		if r == nil {
+			return nil, errors.New("subrequest is required")
+		}
+		if r.Method == "" {
+			return nil, errors.New("subrequest method is required")
+		}
+		if r.URL == "" {
+			return nil, errors.New("subrequest url is required")
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(map[string]interface{}{
+		"haltOnError":   input.HaltOnError,
+		"batchRequests": input.Subrequests,
+	}); err != nil {
+		return nil, fmt.Errorf("couldn't marshal composite batch request: %v", err)
+	}
+	var result struct {
+		HasErrors bool                         `json:"hasErrors"`
+		Results   []*CompositeBatchSubresponse `json:"results"`
+	}
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPost,
+		APIPath: fmt.Sprintf(compositeBatchPath, c.sess.APIVersion),
+		Body:    buf,
+	}, request.JSONResult, &result, http.StatusOK)
+	err := req.Send()
+	return &CompositeBatchOutput{result.HasErrors, result.Results}, err
+}
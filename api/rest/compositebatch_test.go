@@ -0,0 +1,104 @@
+package restapi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/Laugusti/go-sforce/sforce/sforceerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompositeBatch(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	getAccount := &CompositeBatchSubrequest{Method: "GET", URL: "vmock/sobjects/Account/001"}
+	updateAccount := &CompositeBatchSubrequest{Method: "PATCH", URL: "vmock/sobjects/Account/002",
+		RichInput: SObject{"Name": "Acme"}}
+	tooMany := make([]*CompositeBatchSubrequest, 26)
+	for i := range tooMany {
+		tooMany[i] = getAccount
+	}
+
+	tests := []struct {
+		haltOnError  bool
+		subrequests  []*CompositeBatchSubrequest
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{false, nil, 0, 0, "subrequests are required"},
+		{false, tooMany, 0, 0, "too many subrequests"},
+		{false, []*CompositeBatchSubrequest{nil}, 0, 0, "subrequest is required"},
+		{false, []*CompositeBatchSubrequest{{URL: "url"}}, 0, 0, "subrequest method is required"},
+		{false, []*CompositeBatchSubrequest{{Method: "GET"}}, 0, 0, "subrequest url is required"},
+		{true, []*CompositeBatchSubrequest{getAccount, updateAccount}, 200, 1, ""},
+		{false, []*CompositeBatchSubrequest{getAccount, updateAccount}, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/composite/batch", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.PathValidator{Path: path}, postMethodValidator,
+			&testserver.JSONBodyValidator{Body: map[string]interface{}{
+				"haltOnError":   test.haltOnError,
+				"batchRequests": test.subrequests,
+			}}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.CompositeBatch(&CompositeBatchInput{
+				HaltOnError: test.haltOnError,
+				Subrequests: test.subrequests,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*CompositeBatchOutput)
+			if !assert.True(t, ok, assertMsg) || !assert.Len(t, out.Results, 2, assertMsg) {
+				return
+			}
+			assert.True(t, out.HasErrors, assertMsg)
+			var sobj SObject
+			assert.Nil(t, out.Results[0].Err(), assertMsg)
+			assert.Nil(t, out.Results[0].Decode(&sobj), assertMsg)
+			assert.Equal(t, SObject{"Id": "001"}, sobj, assertMsg)
+
+			errs := out.Errors()
+			if assert.Len(t, errs, 1, assertMsg) {
+				apiErrs, ok := errs[1].(sforceerr.APIErrors)
+				if assert.True(t, ok, assertMsg) && assert.Len(t, apiErrs, 1, assertMsg) {
+					assert.Equal(t, "ENTITY_IS_DELETED", apiErrs[0].ErrorCode, assertMsg)
+					assert.Equal(t, 404, apiErrs[0].ActualStatusCode, assertMsg)
+				}
+			}
+		}
+		handler := &testserver.JSONResponseHandler{
+			StatusCode: test.statusCode,
+			Body: map[string]interface{}{
+				"hasErrors": true,
+				"results": []interface{}{
+					map[string]interface{}{"statusCode": 200, "result": SObject{"Id": "001"}},
+					map[string]interface{}{"statusCode": 404, "result": []sforceerr.APIError{
+						{ErrorCode: "ENTITY_IS_DELETED", Message: "entity is deleted"}}},
+				},
+			},
+		}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestBatchSubrequest(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	sub, err := client.NewUpdateSObjectSubrequest("ref", &UpdateSObjectInput{
+		SObjectName: "Account", SObjectID: "001", SObject: SObject{"Name": "Acme"}})
+	if assert.Nil(t, err) {
+		assert.Equal(t, &CompositeBatchSubrequest{Method: "PATCH",
+			URL: "mock/sobjects/Account/001", RichInput: SObject{"Name": "Acme"}},
+			sub.BatchSubrequest())
+	}
+}
@@ -0,0 +1,158 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+	"github.com/Laugusti/go-sforce/sforce/sforceerr"
+)
+
+const (
+	compositeGraphPath = "/services/data/%s/composite/graph/"
+
+	// maxGraphNodes is the maximum number of subrequests (nodes) in a composite graph.
+	maxGraphNodes = 500
+)
+
+// CompositeGraph is a group of subrequests executed in a single transaction as part of a
+// composite graph request. Reference ids must be unique within the graph.
+type CompositeGraph struct {
+	GraphID     string                 `json:"graphId"`
+	Subrequests []*CompositeSubrequest `json:"compositeRequest"`
+}
+
+// CompositeGraphResult is the result of a composite graph. If the graph is not successful,
+// all of its subrequests are rolled back.
+type CompositeGraphResult struct {
+	GraphID      string
+	IsSuccessful bool
+	Results      []*CompositeSubresponse
+}
+
+// Result returns the result of the subrequest with the reference id, or nil if there is no
+// such subrequest.
+func (r *CompositeGraphResult) Result(referenceID string) *CompositeSubresponse {
+	return (&CompositeOutput{r.Results}).Result(referenceID)
+}
+
+// Errors returns the errors of the failed subrequests, keyed by reference id.
+func (r *CompositeGraphResult) Errors() map[string]error {
+	errs := make(map[string]error)
+	for _, res := range r.Results {
+		if err := res.Err(); err != nil {
+			errs[res.ReferenceID] = err
+		}
+	}
+	return errs
+}
+
+// Err returns nil if the graph is successful. Otherwise, the error is a sforceerr.APIErrors
+// containing the errors of the failed subrequests.
+func (r *CompositeGraphResult) Err() error {
+	if r.IsSuccessful {
+		return nil
+	}
+	var errs sforceerr.APIErrors
+	for _, res := range r.Results {
+		switch err := res.Err().(type) {
+		case nil:
+		case sforceerr.APIErrors:
+			errs = append(errs, err...)
+		default:
+			errs = append(errs, &sforceerr.APIError{Message: err.Error(),
+				ActualStatusCode: res.HTTPStatusCode})
+		}
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("graph %q failed", r.GraphID)
+	}
+	return errs
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *CompositeGraphResult) UnmarshalJSON(data []byte) error {
+	var v struct {
+		GraphID       string `json:"graphId"`
+		IsSuccessful  bool   `json:"isSuccessful"`
+		GraphResponse struct {
+			CompositeResponse []*CompositeSubresponse `json:"compositeResponse"`
+		} `json:"graphResponse"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*r = CompositeGraphResult{v.GraphID, v.IsSuccessful, v.GraphResponse.CompositeResponse}
+	return nil
+}
+
+// CompositeGraphInput stores the input for executing a composite graph request.
+type CompositeGraphInput struct {
+	Graphs []*CompositeGraph
+}
+
+// CompositeGraphOutput stores the output after executing a composite graph request. The
+// results are in the same order as the graphs.
+type CompositeGraphOutput struct {
+	Graphs []*CompositeGraphResult
+}
+
+// Graph returns the result of the graph with the graph id, or nil if there is no such graph.
+func (o *CompositeGraphOutput) Graph(graphID string) *CompositeGraphResult {
+	for _, g := range o.Graphs {
+		if g.GraphID == graphID {
+			return g
+		}
+	}
+	return nil
+}
+
+// CompositeGraph executes the graphs of subrequests in a single call to the Salesforce API.
+// Each graph is executed in its own transaction, so a failed graph does not roll back the
+// other graphs.
+func (c *Client) CompositeGraph(input *CompositeGraphInput) (*CompositeGraphOutput, error) {
+	// validate parameters
+	if len(input.Graphs) == 0 {
+		return nil, errors.New("graphs are required")
+	}
+	graphIDs := make(map[string]bool)
+	for _, g := range input.Graphs {
+		if g == nil {
+			return nil, errors.New("graph is required")
+		}
+		if g.GraphID == "" {
+			return nil, errors.New("graph id is required")
+		}
+		if graphIDs[g.GraphID] {
+			return nil, fmt.Errorf("duplicate graph id %q", g.GraphID)
+		}
+		graphIDs[g.GraphID] = true
+		if len(g.Subrequests) == 0 {
+			return nil, fmt.Errorf("graph %q: subrequests are required", g.GraphID)
+		}
+		if len(g.Subrequests) > maxGraphNodes {
+			return nil, fmt.Errorf("graph %q: too many subrequests (max %d)", g.GraphID, maxGraphNodes)
+		}
+		if err := validateSubrequests(g.Subrequests); err != nil {
+			return nil, fmt.Errorf("graph %q: %v", g.GraphID, err)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(map[string]interface{}{"graphs": input.Graphs}); err != nil {
+		return nil, fmt.Errorf("couldn't marshal composite graph request: %v", err)
+	}
+	var result struct {
+		Graphs []*CompositeGraphResult `json:"graphs"`
+	}
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPost,
+		APIPath: fmt.Sprintf(compositeGraphPath, c.sess.APIVersion),
+		Body:    buf,
+	}, request.JSONResult, &result, http.StatusOK)
+	err := req.Send()
+	return &CompositeGraphOutput{result.Graphs}, err
+}
@@ -0,0 +1,167 @@
+package restapi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/Laugusti/go-sforce/sforce/sforceerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompositeGraph(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	createAccount := &CompositeSubrequest{Method: "POST",
+		URL:         "/services/data/mock/sobjects/Account",
+		ReferenceID: "NewAccount", Body: SObject{"Name": "Acme"}}
+	createContact := &CompositeSubrequest{Method: "POST",
+		URL:         "/services/data/mock/sobjects/Contact",
+		ReferenceID: "NewContact", Body: SObject{"AccountId": "@{NewAccount.id}"}}
+	graphs := []*CompositeGraph{
+		{GraphID: "g1", Subrequests: []*CompositeSubrequest{createAccount, createContact}},
+		{GraphID: "g2", Subrequests: []*CompositeSubrequest{createAccount}},
+	}
+	tooMany := make([]*CompositeSubrequest, 501)
+	for i := range tooMany {
+		tooMany[i] = &CompositeSubrequest{Method: "GET", URL: "url",
+			ReferenceID: fmt.Sprintf("ref%d", i)}
+	}
+
+	tests := []struct {
+		graphs       []*CompositeGraph
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{nil, 0, 0, "graphs are required"},
+		{[]*CompositeGraph{nil}, 0, 0, "graph is required"},
+		{[]*CompositeGraph{{Subrequests: []*CompositeSubrequest{createAccount}}}, 0, 0, "graph id is required"},
+		{[]*CompositeGraph{graphs[1], graphs[1]}, 0, 0, `duplicate graph id "g2"`},
+		{[]*CompositeGraph{{GraphID: "g"}}, 0, 0, `graph "g": subrequests are required`},
+		{[]*CompositeGraph{{GraphID: "g", Subrequests: tooMany}}, 0, 0, `graph "g": too many subrequests`},
+		{[]*CompositeGraph{{GraphID: "g", Subrequests: []*CompositeSubrequest{createAccount, createAccount}}},
+			0, 0, `graph "g": duplicate reference id`},
+		{graphs, 200, 1, ""},
+		{graphs, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/composite/graph", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.PathValidator{Path: path}, postMethodValidator,
+			&testserver.JSONBodyValidator{Body: map[string]interface{}{
+				"graphs": []interface{}{
+					map[string]interface{}{"graphId": "g1",
+						"compositeRequest": []*CompositeSubrequest{createAccount, createContact}},
+					map[string]interface{}{"graphId": "g2",
+						"compositeRequest": []*CompositeSubrequest{createAccount}},
+				},
+			}}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.CompositeGraph(&CompositeGraphInput{Graphs: test.graphs})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*CompositeGraphOutput)
+			if !assert.True(t, ok, assertMsg) || !assert.Len(t, out.Graphs, 2, assertMsg) {
+				return
+			}
+			assert.Nil(t, out.Graph("missing"), assertMsg)
+
+			// failed graph
+			g1 := out.Graph("g1")
+			if assert.NotNil(t, g1, assertMsg) {
+				assert.False(t, g1.IsSuccessful, assertMsg)
+				assert.NotNil(t, g1.Result("NewAccount"), assertMsg)
+				errs, ok := g1.Err().(sforceerr.APIErrors)
+				if assert.True(t, ok, assertMsg) && assert.Len(t, errs, 2, assertMsg) {
+					assert.Equal(t, "PROCESSING_HALTED", errs[0].ErrorCode, assertMsg)
+					assert.Equal(t, "REQUIRED_FIELD_MISSING", errs[1].ErrorCode, assertMsg)
+				}
+				nodeErrs := g1.Errors()
+				assert.Len(t, nodeErrs, 2, assertMsg)
+				assert.Contains(t, nodeErrs["NewContact"].Error(), "REQUIRED_FIELD_MISSING", assertMsg)
+			}
+
+			// successful graph
+			g2 := out.Graph("g2")
+			if assert.NotNil(t, g2, assertMsg) {
+				assert.True(t, g2.IsSuccessful, assertMsg)
+				assert.Nil(t, g2.Err(), assertMsg)
+				assert.Empty(t, g2.Errors(), assertMsg)
+				var result UpsertResult
+				assert.Nil(t, g2.Result("NewAccount").Decode(&result), assertMsg)
+				assert.Equal(t, "001", result.ID, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{
+			StatusCode: test.statusCode,
+			Body: map[string]interface{}{
+				"graphs": []interface{}{
+					map[string]interface{}{
+						"graphId":      "g1",
+						"isSuccessful": false,
+						"graphResponse": map[string]interface{}{
+							"compositeResponse": []interface{}{
+								map[string]interface{}{
+									"body": []sforceerr.APIError{{ErrorCode: "PROCESSING_HALTED",
+										Message: "The transaction was rolled back"}},
+									"httpStatusCode": 400,
+									"referenceId":    "NewAccount",
+								},
+								map[string]interface{}{
+									"body": []sforceerr.APIError{{ErrorCode: "REQUIRED_FIELD_MISSING",
+										Message: "Required fields are missing", Fields: []string{"LastName"}}},
+									"httpStatusCode": 400,
+									"referenceId":    "NewContact",
+								},
+							},
+						},
+					},
+					map[string]interface{}{
+						"graphId":      "g2",
+						"isSuccessful": true,
+						"graphResponse": map[string]interface{}{
+							"compositeResponse": []interface{}{
+								map[string]interface{}{
+									"body":           UpsertResult{ID: "001", Success: true},
+									"httpStatusCode": 201,
+									"referenceId":    "NewAccount",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestCompositeGraphResultErr(t *testing.T) {
+	tests := []struct {
+		result     *CompositeGraphResult
+		errSnippet string
+	}{
+		{&CompositeGraphResult{GraphID: "g", IsSuccessful: true}, ""},
+		{&CompositeGraphResult{GraphID: "g"}, `graph "g" failed`},
+		{&CompositeGraphResult{GraphID: "g", Results: []*CompositeSubresponse{
+			{HTTPStatusCode: 500, ReferenceID: "ref", Body: []byte(`"internal error"`)}}},
+			`subrequest "ref" failed with status code 500`},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		err := test.result.Err()
+		if test.errSnippet == "" {
+			assert.Nil(t, err, assertMsg)
+		} else if assert.Error(t, err, assertMsg) {
+			assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+	"github.com/Laugusti/go-sforce/sforce/sforceerr"
+)
+
+const (
+	compositeTreePath = "/services/data/%s/composite/tree/"
+
+	// maxTreeRecords is the maximum number of records (across all levels) in a sObject tree.
+	maxTreeRecords = 200
+
+	// maxTreeDepth is the maximum number of levels in a sObject tree.
+	maxTreeDepth = 5
+)
+
+// CreateSObjectTreeInput stores the input for creating a tree of SObjects. Each SObject must
+// have a unique attributes.referenceId. Child records are nested under the child
+// relationship name (e.g. Contacts) as a list of SObjects and must have an attributes.type.
+// The type of the root SObjects defaults to SObjectName.
+type CreateSObjectTreeInput struct {
+	SObjectName string
+	SObjects    []SObject
+}
+
+// CreateSObjectTreeOutput stores the output after creating a tree of SObjects. IDs maps the
+// reference id of each created record to its Salesforce id and Errors maps the reference id
+// of each failed record to its error.
+type CreateSObjectTreeOutput struct {
+	HasErrors bool
+	IDs       map[string]string
+	Errors    map[string]error
+}
+
+// CreateSObjectTree creates the SObjects and their nested child records using the sObject
+// Tree resource of the Salesforce API. Either all records are created or none are. If a
+// record fails, the output contains the record errors and the returned error is a
+// sforceerr.APIErrors.
+func (c *Client) CreateSObjectTree(input *CreateSObjectTreeInput) (*CreateSObjectTreeOutput, error) {
+	// validate parameters
+	if isInvalidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+	if len(input.SObjects) == 0 {
+		return nil, errors.New("sobjects are required")
+	}
+	tree := &sObjectTree{refIDs: make(map[string]bool)}
+	records, err := tree.records(input.SObjectName, input.SObjects, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(map[string]interface{}{"records": records}); err != nil {
+		return nil, fmt.Errorf("couldn't marshal sobject tree: %v", err)
+	}
+	var body json.RawMessage
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPost,
+		APIPath: path.Join(fmt.Sprintf(compositeTreePath, c.sess.APIVersion), input.SObjectName),
+		Body:    buf,
+	}, request.JSONResult, &body, http.StatusCreated, http.StatusBadRequest)
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+	statusCode := req.Response().StatusCode
+
+	var result struct {
+		HasErrors bool                 `json:"hasErrors"`
+		Results   []*SObjectTreeResult `json:"results"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		// not a tree response (e.g. a list of api errors)
+		if err := subresponseErr("sobject tree request", statusCode, body); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	out := &CreateSObjectTreeOutput{
+		HasErrors: result.HasErrors,
+		IDs:       make(map[string]string),
+		Errors:    make(map[string]error),
+	}
+	var errs sforceerr.APIErrors
+	for _, r := range result.Results {
+		if len(r.Errors) == 0 {
+			out.IDs[r.ReferenceID] = r.ID
+			continue
+		}
+		recordErrs := saveErrors(r.Errors)
+		for _, e := range recordErrs {
+			e.ActualStatusCode = statusCode
+		}
+		out.Errors[r.ReferenceID] = recordErrs
+		errs = append(errs, recordErrs...)
+	}
+	if len(errs) > 0 {
+		return out, errs
+	}
+	if result.HasErrors || statusCode != http.StatusCreated {
+		return out, fmt.Errorf("sobject tree request failed with status code %d: %s", statusCode, body)
+	}
+	return out, nil
+}
+
+// sObjectTree validates and prepares the records of a sObject tree request.
+type sObjectTree struct {
+	count  int
+	refIDs map[string]bool
+}
+
+// records returns copies of the SObjects with the type attribute set and the child records
+// wrapped in a records object. The type defaults to the sobject name if not empty.
+func (t *sObjectTree) records(sobjectName string, sobjs []SObject, depth int) ([]SObject, error) {
+	if depth > maxTreeDepth {
+		return nil, fmt.Errorf("sobject tree is too deep (max %d levels)", maxTreeDepth)
+	}
+	records := make([]SObject, 0, len(sobjs))
+	for _, sobj := range sobjs {
+		t.count++
+		if t.count > maxTreeRecords {
+			return nil, fmt.Errorf("too many records in sobject tree (max %d)", maxTreeRecords)
+		}
+		if len(sobj) == 0 {
+			return nil, errors.New("sobject value is required")
+		}
+
+		// validate attributes
+		attrs := sObjectAttributes(sobj)
+		if attrs["type"] == nil && sobjectName != "" {
+			attrs["type"] = sobjectName
+		}
+		objType, _ := attrs["type"].(string)
+		if objType == "" {
+			return nil, errors.New("sobject type attribute is required")
+		}
+		if sobjectName != "" && objType != sobjectName {
+			return nil, fmt.Errorf("sobject type %q does not match %q", objType, sobjectName)
+		}
+		refID, _ := attrs["referenceId"].(string)
+		if !referenceIDRE.MatchString(refID) {
+			return nil, fmt.Errorf("invalid reference id %q", refID)
+		}
+		if t.refIDs[refID] {
+			return nil, fmt.Errorf("duplicate reference id %q", refID)
+		}
+		t.refIDs[refID] = true
+
+		// copy fields and child records
+		rec := make(SObject, len(sobj))
+		for k, v := range sobj {
+			children, ok := childRecords(v)
+			if !ok {
+				rec[k] = v
+				continue
+			}
+			childRecs, err := t.records("", children, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			rec[k] = map[string]interface{}{"records": childRecs}
+		}
+		rec["attributes"] = attrs
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// sObjectAttributes returns a copy of the attributes of the SObject.
+func sObjectAttributes(sobj SObject) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	switch v := sobj["attributes"].(type) {
+	case map[string]interface{}:
+		for k, a := range v {
+			attrs[k] = a
+		}
+	case SObject:
+		for k, a := range v {
+			attrs[k] = a
+		}
+	}
+	return attrs
+}
+
+// childRecords returns the records if the field value is a list of child records, either as
+// a list of SObjects or wrapped in a records object.
+func childRecords(v interface{}) ([]SObject, bool) {
+	switch v := v.(type) {
+	case []SObject:
+		return v, true
+	case []interface{}:
+		if len(v) == 0 {
+			return nil, false
+		}
+		records := make([]SObject, len(v))
+		for i, r := range v {
+			switch r := r.(type) {
+			case SObject:
+				records[i] = r
+			case map[string]interface{}:
+				records[i] = r
+			default:
+				return nil, false
+			}
+		}
+		return records, true
+	case SObject:
+		return childRecords(map[string]interface{}(v))
+	case map[string]interface{}:
+		if len(v) != 1 || v["records"] == nil {
+			return nil, false
+		}
+		return childRecords(v["records"])
+	}
+	return nil, false
+}
@@ -0,0 +1,150 @@
+package restapi
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/Laugusti/go-sforce/sforce/sforceerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSObjectTree(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	ref := func(id string) map[string]interface{} {
+		return map[string]interface{}{"referenceId": id}
+	}
+	contact := func(id string) SObject {
+		return SObject{"attributes": map[string]interface{}{"type": "Contact", "referenceId": id},
+			"LastName": id}
+	}
+	tree := []SObject{
+		{"attributes": ref("acc1"), "Name": "Acme", "Contacts": []SObject{contact("con1"), contact("con2")}},
+		{"attributes": ref("acc2"), "Name": "Globex",
+			"Contacts": map[string]interface{}{"records": []interface{}{map[string]interface{}(contact("con3"))}}},
+	}
+	deep := SObject{"attributes": ref("l1")}
+	for i, parent := 2, deep; i <= 6; i++ {
+		child := SObject{"attributes": map[string]interface{}{"type": "Account",
+			"referenceId": fmt.Sprintf("l%d", i)}}
+		parent["ChildAccounts"] = []SObject{child}
+		parent = child
+	}
+	tooMany := make([]SObject, 201)
+	for i := range tooMany {
+		tooMany[i] = SObject{"attributes": ref(fmt.Sprintf("ref%d", i))}
+	}
+
+	tests := []struct {
+		objectType   string
+		sobjs        []SObject
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", tree, 0, 0, "invalid sobject name"},
+		{"Account", nil, 0, 0, "sobjects are required"},
+		{"Account", []SObject{{}}, 0, 0, "sobject value is required"},
+		{"Account", tooMany, 0, 0, "too many records in sobject tree"},
+		{"Account", []SObject{deep}, 0, 0, "sobject tree is too deep"},
+		{"Account", []SObject{{"Name": "Acme"}}, 0, 0, `invalid reference id ""`},
+		{"Account", []SObject{{"attributes": ref("a")}, {"attributes": ref("a")}}, 0, 0,
+			`duplicate reference id "a"`},
+		{"Account", []SObject{{"attributes": map[string]interface{}{"type": "Contact",
+			"referenceId": "a"}}}, 0, 0, `sobject type "Contact" does not match "Account"`},
+		{"Account", []SObject{{"attributes": ref("a"), "Contacts": []SObject{{"attributes": ref("b")}}}},
+			0, 0, "sobject type attribute is required"},
+		{"Account", tree, 201, 1, ""},
+		{"Account", tree, 500, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/composite/tree/Account", apiVersion)
+		accountAttrs := func(id string) map[string]interface{} {
+			return map[string]interface{}{"type": "Account", "referenceId": id}
+		}
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.PathValidator{Path: path}, postMethodValidator,
+			&testserver.JSONBodyValidator{Body: map[string]interface{}{
+				"records": []SObject{
+					{"attributes": accountAttrs("acc1"), "Name": "Acme", "Contacts": map[string]interface{}{
+						"records": []SObject{contact("con1"), contact("con2")}}},
+					{"attributes": accountAttrs("acc2"), "Name": "Globex", "Contacts": map[string]interface{}{
+						"records": []SObject{contact("con3")}}},
+				},
+			}}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.CreateSObjectTree(&CreateSObjectTreeInput{
+				SObjectName: test.objectType,
+				SObjects:    test.sobjs,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*CreateSObjectTreeOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.False(t, out.HasErrors, assertMsg)
+				assert.Equal(t, map[string]string{"acc1": "001", "con1": "003"}, out.IDs, assertMsg)
+				assert.Empty(t, out.Errors, assertMsg)
+			}
+			// input not modified
+			assert.Equal(t, ref("acc1"), tree[0]["attributes"], assertMsg)
+		}
+		handler := &testserver.JSONResponseHandler{
+			StatusCode: test.statusCode,
+			Body: map[string]interface{}{
+				"hasErrors": false,
+				"results": []*SObjectTreeResult{
+					{ReferenceID: "acc1", ID: "001"},
+					{ReferenceID: "con1", ID: "003"},
+				},
+			},
+		}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestCreateSObjectTreeErrors(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	server.HandlerFunc = testserver.StaticJSONHandlerFunc(t, http.StatusBadRequest, map[string]interface{}{
+		"hasErrors": true,
+		"results": []*SObjectTreeResult{
+			{ReferenceID: "acc1", Errors: []*SaveError{{StatusCode: "INVALID_EMAIL_ADDRESS",
+				Message: "Email: invalid email address", Fields: []string{"Email"}}}},
+		},
+	})
+	out, err := client.CreateSObjectTree(&CreateSObjectTreeInput{
+		SObjectName: "Account",
+		SObjects:    []SObject{{"attributes": map[string]interface{}{"referenceId": "acc1"}}},
+	})
+	errs, ok := err.(sforceerr.APIErrors)
+	if assert.True(t, ok) && assert.Len(t, errs, 1) {
+		assert.Equal(t, "INVALID_EMAIL_ADDRESS", errs[0].ErrorCode)
+		assert.Equal(t, http.StatusBadRequest, errs[0].ActualStatusCode)
+	}
+	if assert.NotNil(t, out) {
+		assert.True(t, out.HasErrors)
+		assert.Empty(t, out.IDs)
+		assert.Equal(t, errs, out.Errors["acc1"])
+	}
+
+	// api errors
+	server.HandlerFunc = testserver.StaticJSONHandlerFunc(t, http.StatusBadRequest,
+		[]sforceerr.APIError{{ErrorCode: "INVALID_TYPE", Message: "invalid type"}})
+	out, err = client.CreateSObjectTree(&CreateSObjectTreeInput{
+		SObjectName: "Account",
+		SObjects:    []SObject{{"attributes": map[string]interface{}{"referenceId": "acc1"}}},
+	})
+	assert.Nil(t, out)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "INVALID_TYPE")
+	}
+}
@@ -0,0 +1,92 @@
+package restapi
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+// DescribeGlobalInput stores the input for describing the available SObjects.
+// If IfModifiedSince is set, the result is only returned if the metadata changed since then.
+type DescribeGlobalInput struct {
+	IfModifiedSince time.Time
+}
+
+// DescribeGlobalOutput stores the output after describing the available SObjects.
+// NotModified is true (and Result is nil) if the metadata has not changed since the
+// IfModifiedSince time of the input.
+type DescribeGlobalOutput struct {
+	Result      *DescribeGlobalResult
+	NotModified bool
+}
+
+// DescribeGlobal lists the SObjects available in the organization using the Salesforce API.
+func (c *Client) DescribeGlobal(input *DescribeGlobalInput) (*DescribeGlobalOutput, error) {
+	var result DescribeGlobalResult
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodGet,
+		APIPath: c.sObjectPath(),
+		Header:  ifModifiedSinceHeader(input.IfModifiedSince),
+	}, request.JSONResult, &result, http.StatusOK, http.StatusNotModified)
+	if err := req.Send(); err != nil {
+		return &DescribeGlobalOutput{Result: &result}, err
+	}
+	if isNotModified(req) {
+		return &DescribeGlobalOutput{NotModified: true}, nil
+	}
+	return &DescribeGlobalOutput{Result: &result}, nil
+}
+
+// DescribeSObjectInput stores the input for describing a SObject.
+// If IfModifiedSince is set, the result is only returned if the metadata changed since then.
+type DescribeSObjectInput struct {
+	SObjectName     string
+	IfModifiedSince time.Time
+}
+
+// DescribeSObjectOutput stores the output after describing a SObject.
+// NotModified is true (and Result is nil) if the metadata has not changed since the
+// IfModifiedSince time of the input.
+type DescribeSObjectOutput struct {
+	Result      *DescribeSObjectResult
+	NotModified bool
+}
+
+// DescribeSObject retrieves the metadata (fields, relationships, record types, etc.) of the
+// SObject using the Salesforce API.
+func (c *Client) DescribeSObject(input *DescribeSObjectInput) (*DescribeSObjectOutput, error) {
+	// validate parameters
+	if isInvalidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+
+	var result DescribeSObjectResult
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodGet,
+		APIPath: c.sObjectPath(input.SObjectName, "describe"),
+		Header:  ifModifiedSinceHeader(input.IfModifiedSince),
+	}, request.JSONResult, &result, http.StatusOK, http.StatusNotModified)
+	if err := req.Send(); err != nil {
+		return &DescribeSObjectOutput{Result: &result}, err
+	}
+	if isNotModified(req) {
+		return &DescribeSObjectOutput{NotModified: true}, nil
+	}
+	return &DescribeSObjectOutput{Result: &result}, nil
+}
+
+// ifModifiedSinceHeader returns the If-Modified-Since header for the time. Returns nil for
+// the zero time.
+func ifModifiedSinceHeader(t time.Time) http.Header {
+	if t.IsZero() {
+		return nil
+	}
+	return http.Header{"If-Modified-Since": {t.UTC().Format(http.TimeFormat)}}
+}
+
+// isNotModified returns true if the response to the request was 304 (Not Modified).
+func isNotModified(req *request.Request) bool {
+	return req.Response() != nil && req.Response().StatusCode == http.StatusNotModified
+}
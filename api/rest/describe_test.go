@@ -0,0 +1,127 @@
+package restapi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeGlobal(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		statusCode   int
+		requestCount int
+		errSnippet   string
+		want         *DescribeGlobalResult
+	}{
+		{200, 1, "", &DescribeGlobalResult{Encoding: "UTF-8", MaxBatchSize: 200,
+			SObjects: []*DescribeGlobalSObject{
+				{Name: "Account", Label: "Account", KeyPrefix: "001", Queryable: true,
+					URLs: map[string]string{"sobject": "/services/data/mock/sobjects/Account"}},
+				{Name: "Object__c", Custom: true, Createable: true},
+			}}},
+		{400, 1, "GENERIC_ERROR", nil},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/sobjects", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, emptyBodyValidator, &testserver.PathValidator{Path: path},
+			getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.DescribeGlobal(&DescribeGlobalInput{})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*DescribeGlobalOutput)
+			if assert.True(t, ok, assertMsg) && assert.NotNil(t, out, assertMsg) {
+				assert.Equal(t, test.want, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{
+			StatusCode: test.statusCode,
+			Body:       test.want,
+		}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestDescribeSObject(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	account := &DescribeSObjectResult{
+		Name:      "Account",
+		KeyPrefix: "001",
+		Fields: []*DescribeField{
+			{Name: "Id", Type: "id", Length: 18, IDLookup: true},
+			{Name: "Name", Type: "string", Length: 255, Createable: true, Updateable: true,
+				NameField: true},
+			{Name: "Type", Type: "picklist", Nillable: true, PicklistValues: []*PicklistValue{
+				{Active: true, Label: "Customer", Value: "Customer"}}},
+			{Name: "ParentId", Type: "reference", ReferenceTo: []string{"Account"},
+				RelationshipName: "Parent"},
+			{Name: "AnnualRevenue", Type: "currency", Precision: 18, Scale: 0},
+		},
+		ChildRelationships: []*ChildRelationship{
+			{ChildSObject: "Contact", Field: "AccountId", RelationshipName: "Contacts",
+				CascadeDelete: true},
+		},
+		RecordTypeInfos: []*RecordTypeInfo{
+			{Name: "Master", DeveloperName: "Master", RecordTypeID: "012000000000000AAA",
+				Master: true, Available: true},
+		},
+		URLs: map[string]string{"describe": "/services/data/mock/sobjects/Account/describe"},
+	}
+
+	tests := []struct {
+		objectType   string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+		want         *DescribeSObjectResult
+	}{
+		{"", 0, 0, "invalid sobject name", nil},
+		{"Object__x_", 0, 0, "invalid sobject name", nil},
+		{"Account", 200, 1, "", account},
+		{"Account", 404, 1, "GENERIC_ERROR", nil},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/sobjects/%s/describe", apiVersion,
+			test.objectType)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, emptyBodyValidator, &testserver.PathValidator{Path: path},
+			getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.DescribeSObject(&DescribeSObjectInput{
+				SObjectName: test.objectType,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*DescribeSObjectOutput)
+			if assert.True(t, ok, assertMsg) && assert.NotNil(t, out, assertMsg) {
+				assert.Equal(t, test.want, out.Result, assertMsg)
+				assert.Equal(t, "Parent", out.Result.Field("parentid").RelationshipName,
+					assertMsg)
+				assert.Nil(t, out.Result.Field("Missing"), assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{
+			StatusCode: test.statusCode,
+			Body:       test.want,
+		}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
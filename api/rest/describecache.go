@@ -0,0 +1,249 @@
+package restapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// globalCacheKey is the cache key for the describe global result. SObject names cannot start
+// with an underscore, so it does not conflict with SObject keys.
+const globalCacheKey = "_global"
+
+// DescribeCache caches the describe results of a Client. A cached result is returned until
+// its TTL expires, then it is revalidated using the If-Modified-Since header. A 304 (Not
+// Modified) response renews the cached result without transferring the metadata again.
+// Results can also be stored on disk so they are shared between processes.
+// The cached results must not be modified. DescribeCache is safe for concurrent use.
+type DescribeCache struct {
+	client *Client
+	ttl    time.Duration
+	dir    string
+	now    func() time.Time
+
+	mu      sync.Mutex // guards entries
+	entries map[string]*describeCacheEntry
+}
+
+// describeCacheEntry stores the cached value for a key.
+type describeCacheEntry struct {
+	mu    sync.Mutex // guards value, held while the value is retrieved
+	value *describeCacheValue
+}
+
+// describeCacheValue is a cached describe result. This is stored on disk as JSON.
+type describeCacheValue struct {
+	Global      *DescribeGlobalResult  `json:"global,omitempty"`
+	SObject     *DescribeSObjectResult `json:"sobject,omitempty"`
+	ValidatedAt time.Time              `json:"validatedAt"`
+}
+
+// NewDescribeCache returns a describe cache for the client. Cached results are revalidated
+// once they are older than the ttl; a ttl of zero or less revalidates on every call.
+// If dir is not empty, results are also stored on disk in a subdirectory named after the
+// organization id (e.g. ~/.sforce/cache/<org id>).
+func NewDescribeCache(client *Client, ttl time.Duration, dir string) *DescribeCache {
+	return &DescribeCache{
+		client:  client,
+		ttl:     ttl,
+		dir:     dir,
+		now:     time.Now,
+		entries: make(map[string]*describeCacheEntry),
+	}
+}
+
+// DescribeGlobal returns the list of SObjects available in the organization from the cache,
+// retrieving it from the Salesforce API if needed.
+func (dc *DescribeCache) DescribeGlobal() (*DescribeGlobalResult, error) {
+	value, err := dc.get(globalCacheKey, func(since time.Time) (*describeCacheValue, error) {
+		out, err := dc.client.DescribeGlobal(&DescribeGlobalInput{IfModifiedSince: since})
+		if err != nil || out.NotModified {
+			return nil, err
+		}
+		return &describeCacheValue{Global: out.Result}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.Global, nil
+}
+
+// DescribeSObject returns the metadata of the SObject from the cache, retrieving it from the
+// Salesforce API if needed.
+func (dc *DescribeCache) DescribeSObject(name string) (*DescribeSObjectResult, error) {
+	// validate parameters
+	if isInvalidFieldName(name) {
+		return nil, errors.New("invalid sobject name")
+	}
+
+	value, err := dc.get(sObjectCacheKey(name), func(since time.Time) (*describeCacheValue, error) {
+		out, err := dc.client.DescribeSObject(&DescribeSObjectInput{
+			SObjectName:     name,
+			IfModifiedSince: since,
+		})
+		if err != nil || out.NotModified {
+			return nil, err
+		}
+		return &describeCacheValue{SObject: out.Result}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.SObject, nil
+}
+
+// Invalidate removes the SObject metadata from the cache (memory and disk).
+func (dc *DescribeCache) Invalidate(name string) {
+	if isInvalidFieldName(name) {
+		return
+	}
+	dc.invalidate(sObjectCacheKey(name))
+}
+
+// InvalidateGlobal removes the list of SObjects from the cache (memory and disk).
+func (dc *DescribeCache) InvalidateGlobal() {
+	dc.invalidate(globalCacheKey)
+}
+
+// get returns the cached value for the key, calling describe if the key is not cached or
+// has expired. Describe must return a nil value if the result was not modified since the time.
+func (dc *DescribeCache) get(key string,
+	describe func(ifModifiedSince time.Time) (*describeCacheValue, error)) (*describeCacheValue, error) {
+	e := dc.entry(key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// check disk when not in memory
+	if e.value == nil {
+		e.value = dc.readFile(key)
+	}
+	now := dc.now()
+	if e.value != nil && now.Before(e.value.ValidatedAt.Add(dc.ttl)) {
+		return e.value, nil
+	}
+
+	// retrieve or revalidate value
+	var since time.Time
+	if e.value != nil {
+		since = e.value.ValidatedAt
+	}
+	value, err := describe(since)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		// not modified, renew cached value
+		if e.value == nil {
+			return nil, errors.New("describe result not modified but not cached")
+		}
+		value = &describeCacheValue{Global: e.value.Global, SObject: e.value.SObject}
+	}
+	value.ValidatedAt = now
+	e.value = value
+	dc.writeFile(key, value)
+	return value, nil
+}
+
+// entry returns the cache entry for the key, creating it if necessary.
+func (dc *DescribeCache) entry(key string) *describeCacheEntry {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	e, ok := dc.entries[key]
+	if !ok {
+		e = &describeCacheEntry{}
+		dc.entries[key] = e
+	}
+	return e
+}
+
+// invalidate resets the cached value for the key and removes it from disk. It waits for a
+// value being retrieved, so the value isn't written back to disk afterwards.
+func (dc *DescribeCache) invalidate(key string) {
+	file := dc.cacheFile(key)
+	e := dc.entry(key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.value = nil
+	if file != "" {
+		_ = os.Remove(file)
+	}
+}
+
+// cacheFile returns the path of the cache file for the key. Returns an empty string if the
+// disk cache is disabled or the organization id is unknown.
+func (dc *DescribeCache) cacheFile(key string) string {
+	if dc.dir == "" {
+		return ""
+	}
+	// organization id is only known after login
+	sess := dc.client.sess
+	if !sess.HasToken() {
+		if err := sess.Login(); err != nil {
+			return ""
+		}
+	}
+	if sess.OrgID() == "" {
+		return ""
+	}
+	return filepath.Join(dc.dir, sess.OrgID(), key+".json")
+}
+
+// readFile returns the value stored on disk for the key. Returns nil if the value could not
+// be read.
+func (dc *DescribeCache) readFile(key string) *describeCacheValue {
+	file := dc.cacheFile(key)
+	if file == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	var value describeCacheValue
+	if err := json.Unmarshal(b, &value); err != nil {
+		return nil
+	}
+	return &value
+}
+
+// writeFile stores the value on disk for the key. Errors are ignored as the disk cache is
+// only an optimization.
+func (dc *DescribeCache) writeFile(key string, value *describeCacheValue) {
+	file := dc.cacheFile(key)
+	if file == "" {
+		return
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return
+	}
+	// write to temp file and rename so readers never see a partial file
+	tmp, err := ioutil.TempFile(filepath.Dir(file), key)
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(b)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// sObjectCacheKey returns the cache key for the SObject. SObject names are case insensitive.
+func sObjectCacheKey(name string) string {
+	return strings.ToLower(name)
+}
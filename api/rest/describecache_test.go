@@ -0,0 +1,191 @@
+package restapi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/Laugusti/go-sforce/sforce/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeCache(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	start := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	now := start
+	cache := NewDescribeCache(client, time.Minute, "")
+	cache.now = func() time.Time { return now }
+
+	v1 := &DescribeSObjectResult{Name: "Account", Fields: []*DescribeField{{Name: "Id"}}}
+	v2 := &DescribeSObjectResult{Name: "Account", Fields: []*DescribeField{{Name: "Id"},
+		{Name: "Name"}}}
+
+	tests := []struct {
+		elapsed         time.Duration
+		ifModifiedSince string
+		statusCode      int
+		body            interface{}
+		requestCount    int
+		errSnippet      string
+		want            *DescribeSObjectResult
+	}{
+		// not cached
+		{0, "", 200, v1, 1, "", v1},
+		// cached
+		{30 * time.Second, "", 200, v2, 0, "", v1},
+		// expired, not modified
+		{time.Minute, "Thu, 02 Jan 2020 03:04:05 GMT", 304, nil, 1, "", v1},
+		// renewed
+		{90 * time.Second, "", 200, v2, 0, "", v1},
+		// expired, modified
+		{3 * time.Minute, "Thu, 02 Jan 2020 03:05:05 GMT", 200, v2, 1, "", v2},
+		// expired, error
+		{5 * time.Minute, "Thu, 02 Jan 2020 03:07:05 GMT", 400, genericErr, 1, "GENERIC_ERROR", nil},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		now = start.Add(test.elapsed)
+		path := fmt.Sprintf("/services/data/%s/sobjects/Account/describe", apiVersion)
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: test.body},
+			authTokenValidator, getMethodValidator, &testserver.PathValidator{Path: path},
+			&testserver.HeaderValidator{Key: "If-Modified-Since", Value: test.ifModifiedSince})
+		server.RequestCount = 0
+
+		got, err := cache.DescribeSObject("Account")
+		assert.Equal(t, test.requestCount, server.RequestCount, assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		assert.Nil(t, err, assertMsg)
+		assert.Equal(t, test.want, got, assertMsg)
+	}
+
+	// not modified but not cached
+	cache.Invalidate("Account")
+	server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, "not cached",
+		&testserver.JSONResponseHandler{StatusCode: http.StatusNotModified},
+		&testserver.HeaderValidator{Key: "If-Modified-Since", Value: ""})
+	_, err := cache.DescribeSObject("Account")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "not modified but not cached")
+	}
+
+	// invalid name
+	_, err = cache.DescribeSObject("")
+	assert.Error(t, err)
+}
+
+func TestDescribeCacheDisk(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	dir, err := ioutil.TempDir("", "describecache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// login with identity url for org id
+	server.HandlerFunc = testserver.StaticJSONHandlerFunc(t, http.StatusOK,
+		session.RequestToken{
+			AccessToken: accessToken,
+			InstanceURL: server.URL(),
+			ID:          "https://login.salesforce.com/id/00DORG/005USER",
+		})
+	assert.Nil(t, client.sess.Login())
+
+	want := &DescribeGlobalResult{SObjects: []*DescribeGlobalSObject{{Name: "Account"}}}
+	server.HandlerFunc = testserver.StaticJSONHandlerFunc(t, http.StatusOK, want)
+	server.RequestCount = 0
+
+	got, err := NewDescribeCache(client, time.Hour, dir).DescribeGlobal()
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, 1, server.RequestCount)
+	assert.FileExists(t, filepath.Join(dir, "00DORG", "_global.json"))
+
+	// new cache uses result stored on disk
+	cache := NewDescribeCache(client, time.Hour, dir)
+	got, err = cache.DescribeGlobal()
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, 1, server.RequestCount)
+
+	// invalidate removes file
+	cache.InvalidateGlobal()
+	_, err = os.Stat(filepath.Join(dir, "00DORG", "_global.json"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = cache.DescribeGlobal()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, server.RequestCount)
+
+	// invalidate waits for the result being retrieved, which isn't written back to disk
+	retrieving := make(chan struct{})
+	server.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+		close(retrieving)
+		time.Sleep(20 * time.Millisecond)
+		_ = (&testserver.JSONResponseHandler{StatusCode: http.StatusOK,
+			Body: &DescribeSObjectResult{Name: "Account"}}).Handle(w)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := cache.DescribeSObject("Account")
+		assert.Nil(t, err)
+	}()
+	<-retrieving
+	cache.Invalidate("Account")
+	<-done
+	_, err = os.Stat(filepath.Join(dir, "00DORG", "account.json"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDescribeCacheConcurrent(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	var mu sync.Mutex
+	count := map[string]int{}
+	server.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		count[r.URL.Path]++
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		_ = (&testserver.JSONResponseHandler{
+			StatusCode: http.StatusOK,
+			Body:       &DescribeSObjectResult{Name: "Object"},
+		}).Handle(w)
+	}
+
+	cache := NewDescribeCache(client, time.Hour, "")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			got, err := cache.DescribeSObject(name)
+			assert.Nil(t, err)
+			assert.Equal(t, "Object", got.Name)
+		}([]string{"Account", "account", "ACCOUNT"}[i%3])
+	}
+	wg.Wait()
+
+	// single request, names are case insensitive
+	assert.Len(t, count, 1)
+	for _, c := range count {
+		assert.Equal(t, 1, c)
+	}
+}
@@ -0,0 +1,88 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+// defaultStatusCodes are the expected status codes of a raw request when none are specified.
+var defaultStatusCodes = []int{http.StatusOK, http.StatusCreated, http.StatusAccepted,
+	http.StatusNoContent}
+
+// RawResponse is the response of a raw request to the Salesforce API.
+type RawResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Decode unmarshals the json response body into the value. An empty body is not decoded.
+func (r *RawResponse) Decode(v interface{}) error {
+	if len(bytes.TrimSpace(r.Body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+	return nil
+}
+
+// Do sends a raw request to the api path (e.g. /services/data/v50.0/limits) of the
+// Salesforce API. This allows resources that are not wrapped by the client to be used. The
+// body is sent as is if it is an io.Reader or []byte, otherwise it is marshalled as json.
+// If no expected status codes are specified, a 200, 201, 202 or 204 status code is
+// successful. Other status codes return the Salesforce API error.
+func (c *Client) Do(method, apiPath string, query url.Values, body interface{},
+	expectedStatus ...int) (*RawResponse, error) {
+	// validate parameters
+	if method == "" {
+		return nil, errors.New("method is required")
+	}
+	if apiPath == "" {
+		return nil, errors.New("api path is required")
+	}
+
+	var reqBody io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reqBody = b
+	case []byte:
+		reqBody = bytes.NewReader(b)
+	default:
+		buf := &bytes.Buffer{}
+		if err := json.NewEncoder(buf).Encode(b); err != nil {
+			return nil, fmt.Errorf("couldn't marshal body: %v", err)
+		}
+		reqBody = buf
+	}
+	if len(expectedStatus) == 0 {
+		expectedStatus = defaultStatusCodes
+	}
+	var respBody io.ReadCloser
+	req := c.newRequest(&request.Operation{
+		Method:   strings.ToUpper(method),
+		APIPath:  apiPath,
+		RawQuery: query.Encode(),
+		Body:     reqBody,
+	}, request.StreamResult, &respBody, expectedStatus...)
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+	defer func() { _ = respBody.Close() }()
+
+	data, err := ioutil.ReadAll(respBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	return &RawResponse{req.Response().StatusCode, req.Response().Header, data}, nil
+}
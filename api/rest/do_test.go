@@ -0,0 +1,109 @@
+package restapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDo(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	limitsPath := "/services/data/v50.0/limits"
+	tests := []struct {
+		method         string
+		path           string
+		query          url.Values
+		body           interface{}
+		wantBody       interface{}
+		expectedStatus []int
+		respBody       interface{}
+		statusCode     int
+		requestCount   int
+		errSnippet     string
+	}{
+		{"", limitsPath, nil, nil, nil, nil, nil, 0, 0, "method is required"},
+		{"GET", "", nil, nil, nil, nil, nil, 0, 0, "api path is required"},
+		{"get", limitsPath, url.Values{"a": {"b"}}, nil, nil, nil,
+			map[string]interface{}{"DailyApiRequests": "limit"}, 200, 1, ""},
+		{"POST", limitsPath, url.Values{}, map[string]interface{}{"Name": "Acme"},
+			map[string]interface{}{"Name": "Acme"}, nil, map[string]interface{}{"id": "001"}, 201, 1, ""},
+		{"PATCH", limitsPath, url.Values{}, []byte(`{"Name":"Acme"}`),
+			map[string]interface{}{"Name": "Acme"}, nil, nil, 204, 1, ""},
+		{"PUT", limitsPath, url.Values{}, strings.NewReader(`{"Name":"Acme"}`),
+			map[string]interface{}{"Name": "Acme"}, nil, nil, 204, 1, ""},
+		{"GET", limitsPath, url.Values{}, nil, nil, []int{304}, nil, 304, 1, ""},
+		{"GET", limitsPath, url.Values{}, nil, nil, []int{200}, nil, 201, 1, "GENERIC_ERROR"},
+		{"DELETE", limitsPath, url.Values{}, nil, nil, nil, nil, 404, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		query := test.query
+		if query == nil {
+			query = url.Values{}
+		}
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: query}, &testserver.PathValidator{Path: test.path},
+			&testserver.MethodValidator{Method: strings.ToUpper(test.method)},
+			&testserver.JSONBodyValidator{Body: test.wantBody}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.Do(test.method, test.path, test.query, test.body, test.expectedStatus...)
+		}
+		successFunc := func(res interface{}) {
+			resp, ok := res.(*RawResponse)
+			if !assert.True(t, ok, assertMsg) {
+				return
+			}
+			assert.Equal(t, test.statusCode, resp.StatusCode, assertMsg)
+			var out map[string]interface{}
+			assert.Nil(t, resp.Decode(&out), assertMsg)
+			if test.respBody == nil {
+				assert.Nil(t, out, assertMsg)
+			} else {
+				assert.Equal(t, test.respBody, out, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: test.respBody}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestRawResponseDecode(t *testing.T) {
+	tests := []struct {
+		body       string
+		want       interface{}
+		errSnippet string
+	}{
+		{"", nil, ""},
+		{" \n", nil, ""},
+		{`{"a":"b"}`, map[string]interface{}{"a": "b"}, ""},
+		{`[1]`, nil, "failed to unmarshal response"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		var out map[string]interface{}
+		err := (&RawResponse{Body: []byte(test.body)}).Decode(&out)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		assert.Nil(t, err, assertMsg)
+		if test.want == nil {
+			assert.Nil(t, out, assertMsg)
+		} else {
+			assert.Equal(t, test.want, out, assertMsg)
+		}
+	}
+}
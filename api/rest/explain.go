@@ -0,0 +1,38 @@
+package restapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+// ExplainInput stores the input for retrieving the query plans of a SOQL query.
+type ExplainInput struct {
+	Query string
+}
+
+// ExplainOutput stores the output after retrieving the query plans of a SOQL query.
+type ExplainOutput struct {
+	Result *ExplainResult
+}
+
+// Explain retrieves the query plans the Salesforce query optimizer considered for the SOQL
+// query, without executing the query. The plans are used to check the selectivity of a
+// query before it is run against large objects.
+func (c *Client) Explain(input *ExplainInput) (*ExplainOutput, error) {
+	// validate parameters
+	if input.Query == "" {
+		return nil, errors.New("query string is required")
+	}
+
+	var result ExplainResult
+	req := c.newRequest(&request.Operation{
+		Method:   http.MethodGet,
+		APIPath:  fmt.Sprintf(queryPath, c.sess.APIVersion),
+		RawQuery: "explain=" + url.QueryEscape(input.Query),
+	}, request.JSONResult, &result, http.StatusOK)
+	return &ExplainOutput{&result}, req.Send()
+}
@@ -0,0 +1,69 @@
+package restapi
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+var explainResult = &ExplainResult{
+	Plans: []*QueryPlan{
+		{Cardinality: 2843, Fields: []string{}, LeadingOperationType: "TableScan",
+			Notes: []*QueryPlanNote{{Description: "Not considering filter for optimization because unindexed",
+				Fields: []string{"IsDeleted"}, TableEnumOrID: "Account"}},
+			RelativeCost: 1.1, SObjectCardinality: 2843, SObjectType: "Account"},
+		{Cardinality: 1, Fields: []string{"Name"}, LeadingOperationType: "Index",
+			Notes: []*QueryPlanNote{}, RelativeCost: 0.0003, SObjectCardinality: 2843,
+			SObjectType: "Account"},
+	},
+	SourceQuery: "SELECT Id FROM Account WHERE Name = 'Acme'",
+}
+
+func TestExplain(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		query        string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", 0, 0, "query string is required"},
+		{explainResult.SourceQuery, 200, 1, ""},
+		{explainResult.SourceQuery, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/query", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: url.Values{"explain": []string{test.query}}}, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.Explain(&ExplainInput{Query: test.query})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*ExplainOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, explainResult, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: explainResult}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestExplainResultByCost(t *testing.T) {
+	plans := explainResult.ByCost()
+	assert.Equal(t, []*QueryPlan{explainResult.Plans[1], explainResult.Plans[0]}, plans)
+	// original order is unchanged
+	assert.Equal(t, "TableScan", explainResult.Plans[0].LeadingOperationType)
+	assert.Empty(t, (&ExplainResult{}).ByCost())
+}
@@ -0,0 +1,196 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// QueryIterator iterates over the records of a SOQL query. The next batch of records is
+// retrieved from the Salesforce API when the current batch has been consumed. Cancelling the
+// context of the iterator only applies between batches, see NewQueryIterator.
+// Example:
+//
+//	it := client.QueryIterator(ctx, "SELECT Id FROM Account")
+//	for it.Next() {
+//		fmt.Println(it.Record())
+//	}
+//	if err := it.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+type QueryIterator struct {
+	// Prefetch retrieves the next batch of records in the background while the current
+	// batch is consumed. It must be set before the first call to Next.
+	Prefetch bool
+
+	ctx     context.Context
+	first   func() (*QueryResult, error)
+	more    func(nextRecordsURL string) (*QueryResult, error)
+	result  *QueryResult
+	index   int
+	record  SObject
+	pending chan *queryPage
+	err     error
+}
+
+// queryPage is a batch of query records retrieved by the iterator.
+type queryPage struct {
+	result *QueryResult
+	err    error
+}
+
+// QueryIterator returns an iterator for the records of the SOQL query. No request is made
+// until the first call to Next.
+func (c *Client) QueryIterator(ctx context.Context, soql string) *QueryIterator {
+	return NewQueryIterator(ctx, func() (*QueryResult, error) {
+		out, err := c.Query(&QueryInput{Query: soql})
+		if err != nil {
+			return nil, err
+		}
+		return out.Result, nil
+	}, c.queryMore)
+}
+
+// QueryAllRowsIterator returns an iterator for the records of the SOQL query, including
+// deleted and archived records. No request is made until the first call to Next.
+func (c *Client) QueryAllRowsIterator(ctx context.Context, soql string) *QueryIterator {
+	return NewQueryIterator(ctx, func() (*QueryResult, error) {
+		out, err := c.QueryAllRows(&QueryAllRowsInput{Query: soql})
+		if err != nil {
+			return nil, err
+		}
+		return out.Result, nil
+	}, c.queryMore)
+}
+
+// QueryResultIterator returns an iterator for the records of the query result (e.g. the
+// child records returned by GetRelatedSObjects), retrieving the remaining batches as needed.
+func (c *Client) QueryResultIterator(ctx context.Context, result *QueryResult) *QueryIterator {
+	return NewQueryIterator(ctx, func() (*QueryResult, error) {
+		return result, nil
+	}, c.queryMore)
+}
+
+// QueryAll executes the SOQL query and returns the records from every batch. An error is
+// returned if the query has more than maxRecords records. A maxRecords of zero or less
+// means there is no limit.
+func (c *Client) QueryAll(ctx context.Context, soql string, maxRecords int) ([]SObject, error) {
+	it := c.QueryIterator(ctx, soql)
+	it.Prefetch = true
+	return it.Collect(maxRecords)
+}
+
+// NewQueryIterator returns an iterator that retrieves the first batch of records using first
+// and the remaining batches using more. It allows other clients (e.g. the Tooling API
+// client) to iterate over their query results. No request is made until the first call to
+// Next.
+// The context is checked before each batch is retrieved, and stops the wait for a prefetched
+// batch. It is not passed to first and more, so cancelling it does not interrupt a batch
+// that is already being retrieved. The records of that batch are still returned, and Next
+// returns false with the context error when the following batch would be retrieved.
+func NewQueryIterator(ctx context.Context, first func() (*QueryResult, error),
+	more func(string) (*QueryResult, error)) *QueryIterator {
+	return &QueryIterator{ctx: ctx, first: first, more: more}
+}
+
+// Next advances the iterator to the next record, retrieving the next batch if necessary.
+// It returns false when there are no more records or an error occurred.
+func (it *QueryIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.result == nil || it.index >= len(it.result.Records) {
+		// last batch has been consumed
+		if it.result != nil && !it.hasMore() {
+			it.record = nil
+			return false
+		}
+		page := it.nextPage()
+		if page.err != nil {
+			it.err = page.err
+			it.record = nil
+			return false
+		}
+		it.result, it.index = page.result, 0
+		if it.Prefetch && it.hasMore() {
+			it.prefetch()
+		}
+	}
+	it.record = it.result.Records[it.index]
+	it.index++
+	return true
+}
+
+// Record returns the current record.
+func (it *QueryIterator) Record() SObject {
+	return it.record
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (it *QueryIterator) Err() error {
+	return it.err
+}
+
+// hasMore returns true if the current batch is not the last batch.
+func (it *QueryIterator) hasMore() bool {
+	return !it.result.Done && it.result.NextRecordsURL != ""
+}
+
+// nextPage returns the prefetched batch or retrieves the next batch.
+func (it *QueryIterator) nextPage() *queryPage {
+	if err := it.ctx.Err(); err != nil {
+		return &queryPage{err: err}
+	}
+	// wait for prefetched batch
+	if it.pending != nil {
+		pending := it.pending
+		it.pending = nil
+		select {
+		case page := <-pending:
+			return page
+		case <-it.ctx.Done():
+			return &queryPage{err: it.ctx.Err()}
+		}
+	}
+	if it.result == nil {
+		result, err := it.first()
+		return &queryPage{result, err}
+	}
+	result, err := it.more(it.result.NextRecordsURL)
+	return &queryPage{result, err}
+}
+
+// prefetch retrieves the next batch in the background.
+func (it *QueryIterator) prefetch() {
+	nextRecordsURL := it.result.NextRecordsURL
+	// buffered so the goroutine exits even if the batch is never read
+	it.pending = make(chan *queryPage, 1)
+	go func(pending chan<- *queryPage) {
+		result, err := it.more(nextRecordsURL)
+		pending <- &queryPage{result, err}
+	}(it.pending)
+}
+
+// Collect returns the remaining records in the iterator. An error is returned if the query
+// has more than maxRecords records. A maxRecords of zero or less means there is no limit.
+func (it *QueryIterator) Collect(maxRecords int) ([]SObject, error) {
+	var records []SObject
+	for it.Next() {
+		if maxRecords > 0 && (len(records) == maxRecords || it.result.TotalSize > maxRecords) {
+			return nil, fmt.Errorf("query returned more than %d records", maxRecords)
+		}
+		records = append(records, it.Record())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// queryMore retrieves the next batch of query records.
+func (c *Client) queryMore(nextRecordsURL string) (*QueryResult, error) {
+	out, err := c.QueryMore(&QueryMoreInput{NextRecordsURL: nextRecordsURL})
+	if err != nil {
+		return nil, err
+	}
+	return out.Result, nil
+}
@@ -0,0 +1,163 @@
+package restapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryIterator(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	page := func(done bool, totalSize int, nextURL string, ids ...string) testserver.ResponseHandler {
+		records := []SObject{}
+		for _, id := range ids {
+			records = append(records, SObject{"Id": id})
+		}
+		return &testserver.JSONResponseHandler{
+			StatusCode: http.StatusOK,
+			Body: &QueryResult{
+				Done:           done,
+				TotalSize:      totalSize,
+				NextRecordsURL: nextURL,
+				Records:        records,
+			},
+		}
+	}
+	errorPage := &testserver.JSONResponseHandler{
+		StatusCode: http.StatusBadRequest,
+		Body:       []interface{}{genericErr},
+	}
+
+	tests := []struct {
+		prefetch     bool
+		pages        []testserver.ResponseHandler
+		requestCount int
+		errSnippet   string
+		want         []string
+	}{
+		{false, []testserver.ResponseHandler{page(true, 0, "")}, 1, "", nil},
+		{false, []testserver.ResponseHandler{page(true, 2, "", "a", "b")}, 1, "", []string{"a", "b"}},
+		{false, []testserver.ResponseHandler{page(false, 3, "next/1", "a"),
+			page(false, 3, "next/2", "b"), page(true, 3, "", "c")}, 3, "", []string{"a", "b", "c"}},
+		{true, []testserver.ResponseHandler{page(false, 3, "next/1", "a"),
+			page(false, 3, "next/2", "b"), page(true, 3, "", "c")}, 3, "", []string{"a", "b", "c"}},
+		{false, []testserver.ResponseHandler{page(false, 2, "next/1"),
+			page(true, 2, "", "a", "b")}, 2, "", []string{"a", "b"}},
+		{false, []testserver.ResponseHandler{errorPage}, 1, "GENERIC_ERROR", nil},
+		{true, []testserver.ResponseHandler{page(false, 3, "next/1", "a"), errorPage}, 2,
+			"GENERIC_ERROR", []string{"a"}},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: test.pages},
+			authTokenValidator, getMethodValidator)
+		server.RequestCount = 0
+
+		it := client.QueryIterator(context.Background(), "query")
+		it.Prefetch = test.prefetch
+		var got []string
+		for it.Next() {
+			got = append(got, it.Record()["Id"].(string))
+		}
+		assert.False(t, it.Next(), assertMsg)
+		assert.Nil(t, it.Record(), assertMsg)
+		assert.Equal(t, test.want, got, assertMsg)
+		assert.Equal(t, test.requestCount, server.RequestCount, assertMsg)
+		if test.errSnippet == "" {
+			assert.Nil(t, it.Err(), assertMsg)
+		} else if assert.Error(t, it.Err(), assertMsg) {
+			assert.Contains(t, it.Err().Error(), test.errSnippet, assertMsg)
+		}
+	}
+}
+
+func TestQueryIteratorCanceled(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it := client.QueryIterator(ctx, "query")
+	assert.False(t, it.Next())
+	assert.Equal(t, context.Canceled, it.Err())
+	assert.Equal(t, 0, server.RequestCount)
+}
+
+func TestQueryAll(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		maxRecords   int
+		requestCount int
+		errSnippet   string
+		want         []SObject
+	}{
+		{0, 2, "", []SObject{{"Id": "a"}, {"Id": "b"}, {"Id": "c"}}},
+		{3, 2, "", []SObject{{"Id": "a"}, {"Id": "b"}, {"Id": "c"}}},
+		{2, 1, "query returned more than 2 records", nil},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: []testserver.ResponseHandler{
+				&testserver.JSONResponseHandler{
+					StatusCode: http.StatusOK,
+					Body: &QueryResult{TotalSize: 3, NextRecordsURL: "next",
+						Records: []SObject{{"Id": "a"}, {"Id": "b"}}},
+				},
+				&testserver.JSONResponseHandler{
+					StatusCode: http.StatusOK,
+					Body: &QueryResult{TotalSize: 3, Done: true,
+						Records: []SObject{{"Id": "c"}}},
+				},
+			}})
+		server.RequestCount = 0
+
+		got, err := client.QueryAll(context.Background(), "query", test.maxRecords)
+		if test.errSnippet == "" {
+			assert.Nil(t, err, assertMsg)
+			assert.Equal(t, test.want, got, assertMsg)
+		} else if assert.Error(t, err, assertMsg) {
+			assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+		}
+		// prefetch may have requested the second batch before failing
+		assert.True(t, server.RequestCount >= test.requestCount, assertMsg)
+	}
+}
+
+func TestQueryResultIterator(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, "",
+		&testserver.JSONResponseHandler{
+			StatusCode: http.StatusOK,
+			Body:       &QueryResult{Done: true, TotalSize: 3, Records: []SObject{{"Id": "c"}}},
+		},
+		authTokenValidator, getMethodValidator, &testserver.PathValidator{Path: "/next/1"})
+	server.RequestCount = 0
+
+	it := client.QueryResultIterator(context.Background(), &QueryResult{
+		Done:           false,
+		TotalSize:      3,
+		NextRecordsURL: "/next/1",
+		Records:        []SObject{{"Id": "a"}, {"Id": "b"}},
+	})
+	var got []string
+	for it.Next() {
+		got = append(got, it.Record()["Id"].(string))
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []string{"a", "b", "c"}, got)
+	assert.Equal(t, 1, server.RequestCount)
+}
@@ -0,0 +1,82 @@
+package restapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+const limitsPath = "/services/data/%s/limits/"
+
+// LimitsInput stores the input for retrieving the organization limits.
+type LimitsInput struct{}
+
+// LimitsOutput stores the output after retrieving the organization limits. The limits are
+// keyed by limit name (e.g. DailyApiRequests).
+type LimitsOutput struct {
+	Limits map[string]*Limit
+}
+
+// Limits retrieves the maximum and remaining allocations of the organization limits
+// (API requests, storage, streaming events, etc.) using the Salesforce API.
+func (c *Client) Limits(input *LimitsInput) (*LimitsOutput, error) {
+	var limits map[string]*Limit
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodGet,
+		APIPath: fmt.Sprintf(limitsPath, c.sess.APIVersion),
+	}, request.JSONResult, &limits, http.StatusOK)
+	err := req.Send()
+	return &LimitsOutput{limits}, err
+}
+
+// RecordCountInput stores the input for retrieving the record counts of SObjects. With no
+// SObject names, the counts of all SObjects are retrieved.
+type RecordCountInput struct {
+	SObjectNames []string
+}
+
+// RecordCountOutput stores the output after retrieving the record counts. The counts are
+// keyed by SObject name.
+type RecordCountOutput struct {
+	Counts map[string]int
+}
+
+// RecordCount retrieves the approximate number of records of each SObject using the
+// Salesforce API. This is faster than a COUNT() query for large objects.
+func (c *Client) RecordCount(input *RecordCountInput) (*RecordCountOutput, error) {
+	// validate parameters
+	for _, name := range input.SObjectNames {
+		if isInvalidFieldName(name) {
+			return nil, errors.New("invalid sobject name")
+		}
+	}
+
+	rawQuery := ""
+	if len(input.SObjectNames) > 0 {
+		rawQuery = "sObjects=" + strings.Join(input.SObjectNames, ",")
+	}
+	var result struct {
+		SObjects []struct {
+			Name  string `json:"name"`
+			Count int    `json:"count"`
+		} `json:"sObjects"`
+	}
+	req := c.newRequest(&request.Operation{
+		Method:   http.MethodGet,
+		APIPath:  path.Join(fmt.Sprintf(limitsPath, c.sess.APIVersion), "recordCount"),
+		RawQuery: rawQuery,
+	}, request.JSONResult, &result, http.StatusOK)
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(result.SObjects))
+	for _, s := range result.SObjects {
+		counts[s.Name] = s.Count
+	}
+	return &RecordCountOutput{counts}, nil
+}
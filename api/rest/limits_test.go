@@ -0,0 +1,120 @@
+package restapi
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{200, 1, ""},
+		{400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/limits", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, emptyBodyValidator, &testserver.PathValidator{Path: path},
+			getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.Limits(&LimitsInput{})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*LimitsOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, map[string]*Limit{
+					"DailyApiRequests": {Max: 15000, Remaining: 14000},
+					"DataStorageMB":    {Max: 5, Remaining: 5},
+				}, out.Limits, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{
+			StatusCode: test.statusCode,
+			Body: map[string]interface{}{
+				"DailyApiRequests": map[string]interface{}{"Max": 15000, "Remaining": 14000,
+					"Ant Migration Tool": map[string]interface{}{"Max": 0, "Remaining": 0}},
+				"DataStorageMB": map[string]interface{}{"Max": 5, "Remaining": 5},
+			},
+		}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestLimitPercentRemaining(t *testing.T) {
+	tests := []struct {
+		limit *Limit
+		want  float64
+	}{
+		{&Limit{Max: 0, Remaining: 0}, 100},
+		{&Limit{Max: 200, Remaining: 50}, 25},
+		{&Limit{Max: 10, Remaining: 10}, 100},
+		{&Limit{Max: 10, Remaining: 0}, 0},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, test.limit.PercentRemaining(), fmt.Sprintf("input: %v", test))
+	}
+}
+
+func TestRecordCount(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		names        []string
+		query        url.Values
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{[]string{"Account", "1a"}, nil, 0, 0, "invalid sobject name"},
+		{nil, url.Values{}, 200, 1, ""},
+		{[]string{"Account", "Contact"}, url.Values{"sObjects": {"Account,Contact"}}, 200, 1, ""},
+		{[]string{"Account"}, url.Values{"sObjects": {"Account"}}, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/limits/recordCount", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: test.query}, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.RecordCount(&RecordCountInput{SObjectNames: test.names})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*RecordCountOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, map[string]int{"Account": 3, "Contact": 10}, out.Counts, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{
+			StatusCode: test.statusCode,
+			Body: map[string]interface{}{
+				"sObjects": []interface{}{
+					map[string]interface{}{"name": "Account", "count": 3},
+					map[string]interface{}{"name": "Contact", "count": 10},
+				},
+			},
+		}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
@@ -0,0 +1,85 @@
+package restapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+// GetRelatedSObjectsInput stores the input for retrieving the SObjects related to a SObject
+// through a relationship (e.g. Contacts or Owner).
+type GetRelatedSObjectsInput struct {
+	SObjectName      string
+	SObjectID        string
+	RelationshipName string
+	Fields           []string
+}
+
+// GetRelatedSObjectsOutput stores the output after retrieving related SObjects. SObject is set
+// for a lookup relationship and Result is set for a child relationship. The remaining child
+// records can be retrieved using QueryMore or QueryResultIterator.
+type GetRelatedSObjectsOutput struct {
+	SObject SObject
+	Result  *QueryResult
+}
+
+// GetRelatedSObjects retrieves the SObjects related to the SObject through the relationship
+// using the Salesforce API.
+func (c *Client) GetRelatedSObjects(input *GetRelatedSObjectsInput) (*GetRelatedSObjectsOutput, error) {
+	// validate parameters
+	if isInvalidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+	if input.SObjectID == "" {
+		return nil, errors.New("sobject id is required")
+	}
+	if isInvalidFieldName(input.RelationshipName) {
+		return nil, errors.New("invalid relationship name")
+	}
+	if err := ValidateFields(input.Fields); err != nil {
+		return nil, err
+	}
+
+	var body map[string]json.RawMessage
+	req := c.newRequest(&request.Operation{
+		Method:   http.MethodGet,
+		RawQuery: FieldsQuery(input.Fields),
+		APIPath:  c.sObjectPath(input.SObjectName, input.SObjectID, input.RelationshipName),
+	}, request.JSONResult, &body, http.StatusOK)
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+
+	// child relationship returns a query result
+	var err error
+	out := &GetRelatedSObjectsOutput{}
+	if isQueryResult(body) {
+		err = remarshal(body, &out.Result)
+	} else {
+		err = remarshal(body, &out.SObject)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal related sobjects: %v", err)
+	}
+	return out, nil
+}
+
+// isQueryResult returns true if the json object is a query result.
+func isQueryResult(body map[string]json.RawMessage) bool {
+	_, hasRecords := body["records"]
+	_, hasTotalSize := body["totalSize"]
+	_, hasDone := body["done"]
+	return hasRecords && hasTotalSize && hasDone
+}
+
+// remarshal marshals the json object and unmarshals it into the value.
+func remarshal(body map[string]json.RawMessage, v interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,71 @@
+package restapi
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRelatedSObjects(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	owner := SObject{"Id": "005", "Name": "Jane"}
+	contacts := &QueryResult{TotalSize: 3, Done: false, NextRecordsURL: "/next",
+		Records: []SObject{{"Id": "003"}, {"Id": "004"}}}
+	tests := []struct {
+		relationship string
+		fields       []string
+		body         interface{}
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", nil, nil, 0, 0, "invalid relationship name"},
+		{"Contacts", []string{"Id", "1a"}, nil, 0, 0, "invalid field list"},
+		{"Owner", []string{"Id", "Name"}, owner, 200, 1, ""},
+		{"Contacts", nil, contacts, 200, 1, ""},
+		{"Contacts", nil, contacts, 404, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/sobjects/Account/001/%s", apiVersion, test.relationship)
+		query := url.Values{}
+		if len(test.fields) > 0 {
+			query.Set("fields", "Id,Name")
+		}
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: query}, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.GetRelatedSObjects(&GetRelatedSObjectsInput{
+				SObjectName:      "Account",
+				SObjectID:        "001",
+				RelationshipName: test.relationship,
+				Fields:           test.fields,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*GetRelatedSObjectsOutput)
+			if !assert.True(t, ok, assertMsg) {
+				return
+			}
+			if test.relationship == "Owner" {
+				assert.Equal(t, owner, out.SObject, assertMsg)
+				assert.Nil(t, out.Result, assertMsg)
+			} else {
+				assert.Nil(t, out.SObject, assertMsg)
+				assert.Equal(t, contacts, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: test.body}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
@@ -0,0 +1,171 @@
+package restapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+const (
+	// DateTimeParamLayout is the layout of date/time parameters sent to the Salesforce API.
+	DateTimeParamLayout = "2006-01-02T15:04:05Z"
+
+	// DateTimeLayout is the layout of date/time values returned by the Salesforce API.
+	DateTimeLayout = "2006-01-02T15:04:05.000-0700"
+)
+
+// GetUpdatedInput stores the input for listing the SObjects updated between the start
+// and end time.
+type GetUpdatedInput struct {
+	SObjectName string
+	Start       time.Time
+	End         time.Time
+}
+
+// GetUpdatedOutput stores the output after listing the updated SObjects. LatestDateCovered
+// is the time of the last change covered by the result.
+type GetUpdatedOutput struct {
+	IDs               []string
+	LatestDateCovered time.Time
+}
+
+// GetUpdated lists the ids of the SObjects updated between the start and end time using the
+// Salesforce API.
+func (c *Client) GetUpdated(input *GetUpdatedInput) (*GetUpdatedOutput, error) {
+	// validate parameters
+	if err := validateReplicationRange(input.SObjectName, input.Start, input.End); err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		IDs               []string `json:"ids"`
+		LatestDateCovered string   `json:"latestDateCovered"`
+	}
+	req := c.newRequest(&request.Operation{
+		Method:   http.MethodGet,
+		APIPath:  c.sObjectPath(input.SObjectName, "updated"),
+		RawQuery: replicationQuery(input.Start, input.End),
+	}, request.JSONResult, &result, http.StatusOK)
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+
+	latest, err := parseDateTime(result.LatestDateCovered)
+	if err != nil {
+		return nil, err
+	}
+	return &GetUpdatedOutput{result.IDs, latest}, nil
+}
+
+// DeletedRecord is a SObject deleted between the start and end time of a GetDeleted request.
+type DeletedRecord struct {
+	ID          string
+	DeletedDate time.Time
+}
+
+// GetDeletedInput stores the input for listing the SObjects deleted between the start and
+// end time.
+type GetDeletedInput struct {
+	SObjectName string
+	Start       time.Time
+	End         time.Time
+}
+
+// GetDeletedOutput stores the output after listing the deleted SObjects.
+// EarliestDateAvailable is the time of the earliest deleted record still available and
+// LatestDateCovered is the time of the last change covered by the result.
+type GetDeletedOutput struct {
+	DeletedRecords        []*DeletedRecord
+	EarliestDateAvailable time.Time
+	LatestDateCovered     time.Time
+}
+
+// GetDeleted lists the SObjects deleted between the start and end time using the Salesforce
+// API.
+func (c *Client) GetDeleted(input *GetDeletedInput) (*GetDeletedOutput, error) {
+	// validate parameters
+	if err := validateReplicationRange(input.SObjectName, input.Start, input.End); err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		DeletedRecords []struct {
+			ID          string `json:"id"`
+			DeletedDate string `json:"deletedDate"`
+		} `json:"deletedRecords"`
+		EarliestDateAvailable string `json:"earliestDateAvailable"`
+		LatestDateCovered     string `json:"latestDateCovered"`
+	}
+	req := c.newRequest(&request.Operation{
+		Method:   http.MethodGet,
+		APIPath:  c.sObjectPath(input.SObjectName, "deleted"),
+		RawQuery: replicationQuery(input.Start, input.End),
+	}, request.JSONResult, &result, http.StatusOK)
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+
+	out := &GetDeletedOutput{DeletedRecords: make([]*DeletedRecord, len(result.DeletedRecords))}
+	for i, r := range result.DeletedRecords {
+		deleted, err := parseDateTime(r.DeletedDate)
+		if err != nil {
+			return nil, err
+		}
+		out.DeletedRecords[i] = &DeletedRecord{r.ID, deleted}
+	}
+	var err error
+	if out.EarliestDateAvailable, err = parseDateTime(result.EarliestDateAvailable); err != nil {
+		return nil, err
+	}
+	if out.LatestDateCovered, err = parseDateTime(result.LatestDateCovered); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// validateReplicationRange returns an error if the sobject name or time range is invalid.
+func validateReplicationRange(sobjectName string, start, end time.Time) error {
+	if isInvalidFieldName(sobjectName) {
+		return errors.New("invalid sobject name")
+	}
+	if start.IsZero() {
+		return errors.New("start time is required")
+	}
+	if end.IsZero() {
+		return errors.New("end time is required")
+	}
+	if !end.After(start) {
+		return errors.New("end time must be after start time")
+	}
+	return nil
+}
+
+// replicationQuery returns the raw query for the start and end time.
+func replicationQuery(start, end time.Time) string {
+	return url.Values{
+		"start": {formatDateTime(start)},
+		"end":   {formatDateTime(end)},
+	}.Encode()
+}
+
+// formatDateTime formats the time as a Salesforce date/time parameter in UTC.
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(DateTimeParamLayout)
+}
+
+// parseDateTime parses a Salesforce date/time value. Returns the zero time for an empty
+// value.
+func parseDateTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(DateTimeLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse date/time %q: %v", s, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,158 @@
+package restapi
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUpdated(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	start := time.Date(2020, 5, 5, 1, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+	end := start.Add(24 * time.Hour)
+	tests := []struct {
+		objectType   string
+		start        time.Time
+		end          time.Time
+		latest       string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", start, end, "", 0, 0, "invalid sobject name"},
+		{"Account", time.Time{}, end, "", 0, 0, "start time is required"},
+		{"Account", start, time.Time{}, "", 0, 0, "end time is required"},
+		{"Account", end, start, "", 0, 0, "end time must be after start time"},
+		{"Account", start, end, "2020-05-06T05:30:00.000+0000", 200, 1, ""},
+		{"Account", start, end, "", 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/sobjects/Account/updated", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: url.Values{
+				"start": {"2020-05-05T06:00:00Z"},
+				"end":   {"2020-05-06T06:00:00Z"},
+			}}, emptyBodyValidator, &testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.GetUpdated(&GetUpdatedInput{
+				SObjectName: test.objectType,
+				Start:       test.start,
+				End:         test.end,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*GetUpdatedOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, []string{"001", "002"}, out.IDs, assertMsg)
+				assert.True(t, time.Date(2020, 5, 6, 5, 30, 0, 0, time.UTC).Equal(out.LatestDateCovered),
+					assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{
+			StatusCode: test.statusCode,
+			Body: map[string]interface{}{
+				"ids":               []string{"001", "002"},
+				"latestDateCovered": test.latest,
+			},
+		}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestGetDeleted(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	start := time.Date(2020, 5, 5, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	tests := []struct {
+		objectType   string
+		deletedDate  string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"1a", "", 0, 0, "invalid sobject name"},
+		{"Account", "2020-05-05T10:15:30.000+0000", 200, 1, ""},
+		{"Account", "", 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/sobjects/Account/deleted", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: url.Values{
+				"start": {"2020-05-05T00:00:00Z"},
+				"end":   {"2020-05-06T00:00:00Z"},
+			}}, emptyBodyValidator, &testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.GetDeleted(&GetDeletedInput{
+				SObjectName: test.objectType,
+				Start:       start,
+				End:         end,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*GetDeletedOutput)
+			if !assert.True(t, ok, assertMsg) || !assert.Len(t, out.DeletedRecords, 1, assertMsg) {
+				return
+			}
+			assert.Equal(t, "001", out.DeletedRecords[0].ID, assertMsg)
+			assert.True(t, time.Date(2020, 5, 5, 10, 15, 30, 0, time.UTC).Equal(
+				out.DeletedRecords[0].DeletedDate), assertMsg)
+			assert.True(t, time.Date(2020, 4, 5, 0, 0, 0, 0, time.UTC).Equal(out.EarliestDateAvailable),
+				assertMsg)
+			assert.True(t, time.Date(2020, 5, 5, 23, 0, 0, 0, time.UTC).Equal(out.LatestDateCovered),
+				assertMsg)
+		}
+		handler := &testserver.JSONResponseHandler{
+			StatusCode: test.statusCode,
+			Body: map[string]interface{}{
+				"deletedRecords": []interface{}{
+					map[string]interface{}{"id": "001", "deletedDate": test.deletedDate},
+				},
+				"earliestDateAvailable": "2020-04-05T00:00:00.000+0000",
+				"latestDateCovered":     "2020-05-05T23:00:00.000+0000",
+			},
+		}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestParseDateTime(t *testing.T) {
+	tests := []struct {
+		value      string
+		want       time.Time
+		errSnippet string
+	}{
+		{"", time.Time{}, ""},
+		{"2020-05-05T10:15:30.000+0000", time.Date(2020, 5, 5, 10, 15, 30, 0, time.UTC), ""},
+		{"2020-05-05T10:15:30.000-0500", time.Date(2020, 5, 5, 15, 15, 30, 0, time.UTC), ""},
+		{"yesterday", time.Time{}, `failed to parse date/time "yesterday"`},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		got, err := parseDateTime(test.value)
+		if test.errSnippet == "" {
+			assert.Nil(t, err, assertMsg)
+			assert.True(t, test.want.Equal(got), assertMsg)
+		} else if assert.Error(t, err, assertMsg) {
+			assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+		}
+	}
+}
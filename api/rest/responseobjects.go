@@ -1,5 +1,12 @@
 package restapi
 
+import (
+	"sort"
+	"strings"
+
+	"github.com/Laugusti/go-sforce/sforce/sforceerr"
+)
+
 // UpsertResult is a successful response from the Salesforce API after an upsert.
 type UpsertResult struct {
 	ID      string        `json:"id"`
@@ -7,6 +14,50 @@ type UpsertResult struct {
 	Errors  []interface{} `json:"errors"`
 }
 
+// SaveResult is the result for a record after a sObject collection request. Created is only
+// set by upserts.
+type SaveResult struct {
+	ID      string       `json:"id"`
+	Success bool         `json:"success"`
+	Created bool         `json:"created"`
+	Errors  []*SaveError `json:"errors"`
+}
+
+// Err returns the errors of the record as a sforceerr.APIErrors, or nil if the record was
+// saved.
+func (r *SaveResult) Err() error {
+	if r.Success && len(r.Errors) == 0 {
+		return nil
+	}
+	if len(r.Errors) == 0 {
+		return sforceerr.APIErrors{{Message: "record was not saved"}}
+	}
+	return saveErrors(r.Errors)
+}
+
+// SaveError is an error for a record in a sObject collection request.
+type SaveError struct {
+	StatusCode string   `json:"statusCode"`
+	Message    string   `json:"message"`
+	Fields     []string `json:"fields"`
+}
+
+// saveErrors returns the record errors as a sforceerr.APIErrors.
+func saveErrors(errs []*SaveError) sforceerr.APIErrors {
+	apiErrs := make(sforceerr.APIErrors, len(errs))
+	for i, e := range errs {
+		apiErrs[i] = &sforceerr.APIError{Fields: e.Fields, Message: e.Message, ErrorCode: e.StatusCode}
+	}
+	return apiErrs
+}
+
+// SObjectTreeResult is the result for a record after a sObject tree request.
+type SObjectTreeResult struct {
+	ReferenceID string       `json:"referenceId"`
+	ID          string       `json:"id"`
+	Errors      []*SaveError `json:"errors"`
+}
+
 // QueryResult is successful response from the Salesforce API after a query.
 type QueryResult struct {
 	TotalSize      int       `json:"totalSize"`
@@ -14,3 +65,214 @@ type QueryResult struct {
 	NextRecordsURL string    `json:"nextRecordsURL,omitempty"`
 	Records        []SObject `json:"records"`
 }
+
+// ExplainResult is a successful response from the Salesforce API after explaining a query.
+type ExplainResult struct {
+	Plans       []*QueryPlan `json:"plans"`
+	SourceQuery string       `json:"sourceQuery"`
+}
+
+// ByCost returns the query plans sorted by relative cost, starting with the cheapest. A
+// relative cost above 1 means the query is not selective.
+func (r *ExplainResult) ByCost() []*QueryPlan {
+	plans := make([]*QueryPlan, len(r.Plans))
+	copy(plans, r.Plans)
+	sort.SliceStable(plans, func(i, j int) bool {
+		return plans[i].RelativeCost < plans[j].RelativeCost
+	})
+	return plans
+}
+
+// QueryPlan is a plan considered by the query optimizer for a query. The leading operation
+// type is the primary operation (e.g. Index, TableScan, Sharing) used to optimize the query.
+type QueryPlan struct {
+	Cardinality          int              `json:"cardinality"`
+	Fields               []string         `json:"fields"`
+	LeadingOperationType string           `json:"leadingOperationType"`
+	Notes                []*QueryPlanNote `json:"notes"`
+	RelativeCost         float64          `json:"relativeCost"`
+	SObjectCardinality   int              `json:"sobjectCardinality"`
+	SObjectType          string           `json:"sobjectType"`
+}
+
+// QueryPlanNote is a note about why an optimization was not used by a query plan.
+type QueryPlanNote struct {
+	Description   string   `json:"description"`
+	Fields        []string `json:"fields"`
+	TableEnumOrID string   `json:"tableEnumOrId"`
+}
+
+// SearchResult is a successful response from the Salesforce API after a search.
+type SearchResult struct {
+	SearchRecords []SObject `json:"searchRecords"`
+}
+
+// ByType returns the search records grouped by their SObject type (attributes.type).
+func (r *SearchResult) ByType() map[string][]SObject {
+	groups := make(map[string][]SObject)
+	for _, rec := range r.SearchRecords {
+		objType, _ := sObjectAttributes(rec)["type"].(string)
+		groups[objType] = append(groups[objType], rec)
+	}
+	return groups
+}
+
+// Limit is the maximum and remaining allocation of an organization limit.
+type Limit struct {
+	Max       int `json:"Max"`
+	Remaining int `json:"Remaining"`
+}
+
+// PercentRemaining returns the percentage of the limit remaining. Returns 100 if the
+// maximum is zero.
+func (l *Limit) PercentRemaining() float64 {
+	if l.Max == 0 {
+		return 100
+	}
+	return float64(l.Remaining) / float64(l.Max) * 100
+}
+
+// DescribeGlobalResult is a successful response from the Salesforce API after describing
+// the available objects.
+type DescribeGlobalResult struct {
+	Encoding     string                   `json:"encoding"`
+	MaxBatchSize int                      `json:"maxBatchSize"`
+	SObjects     []*DescribeGlobalSObject `json:"sobjects"`
+}
+
+// DescribeGlobalSObject is the summary of a Salesforce Object in the describe global result.
+type DescribeGlobalSObject struct {
+	Name                string            `json:"name"`
+	Label               string            `json:"label"`
+	LabelPlural         string            `json:"labelPlural"`
+	KeyPrefix           string            `json:"keyPrefix"`
+	Custom              bool              `json:"custom"`
+	CustomSetting       bool              `json:"customSetting"`
+	Activateable        bool              `json:"activateable"`
+	Createable          bool              `json:"createable"`
+	Updateable          bool              `json:"updateable"`
+	Deletable           bool              `json:"deletable"`
+	Undeletable         bool              `json:"undeletable"`
+	Mergeable           bool              `json:"mergeable"`
+	Queryable           bool              `json:"queryable"`
+	Retrieveable        bool              `json:"retrieveable"`
+	Searchable          bool              `json:"searchable"`
+	Layoutable          bool              `json:"layoutable"`
+	Replicateable       bool              `json:"replicateable"`
+	Triggerable         bool              `json:"triggerable"`
+	FeedEnabled         bool              `json:"feedEnabled"`
+	DeprecatedAndHidden bool              `json:"deprecatedAndHidden"`
+	URLs                map[string]string `json:"urls"`
+}
+
+// DescribeSObjectResult is a successful response from the Salesforce API after describing
+// a Salesforce Object.
+type DescribeSObjectResult struct {
+	Name                string               `json:"name"`
+	Label               string               `json:"label"`
+	LabelPlural         string               `json:"labelPlural"`
+	KeyPrefix           string               `json:"keyPrefix"`
+	Custom              bool                 `json:"custom"`
+	CustomSetting       bool                 `json:"customSetting"`
+	Activateable        bool                 `json:"activateable"`
+	Createable          bool                 `json:"createable"`
+	Updateable          bool                 `json:"updateable"`
+	Deletable           bool                 `json:"deletable"`
+	Undeletable         bool                 `json:"undeletable"`
+	Mergeable           bool                 `json:"mergeable"`
+	Queryable           bool                 `json:"queryable"`
+	Retrieveable        bool                 `json:"retrieveable"`
+	Searchable          bool                 `json:"searchable"`
+	Layoutable          bool                 `json:"layoutable"`
+	Replicateable       bool                 `json:"replicateable"`
+	Triggerable         bool                 `json:"triggerable"`
+	FeedEnabled         bool                 `json:"feedEnabled"`
+	DeprecatedAndHidden bool                 `json:"deprecatedAndHidden"`
+	Fields              []*DescribeField     `json:"fields"`
+	ChildRelationships  []*ChildRelationship `json:"childRelationships"`
+	RecordTypeInfos     []*RecordTypeInfo    `json:"recordTypeInfos"`
+	URLs                map[string]string    `json:"urls"`
+}
+
+// Field returns the field with the name, or nil if the object has no such field.
+func (r *DescribeSObjectResult) Field(name string) *DescribeField {
+	for _, f := range r.Fields {
+		if strings.EqualFold(f.Name, name) {
+			return f
+		}
+	}
+	return nil
+}
+
+// DescribeField is the metadata for a field on a Salesforce Object.
+type DescribeField struct {
+	Name                string           `json:"name"`
+	Label               string           `json:"label"`
+	Type                string           `json:"type"`
+	SoapType            string           `json:"soapType"`
+	Length              int              `json:"length"`
+	ByteLength          int              `json:"byteLength"`
+	Digits              int              `json:"digits"`
+	Precision           int              `json:"precision"`
+	Scale               int              `json:"scale"`
+	Nillable            bool             `json:"nillable"`
+	Createable          bool             `json:"createable"`
+	Updateable          bool             `json:"updateable"`
+	Unique              bool             `json:"unique"`
+	ExternalID          bool             `json:"externalId"`
+	IDLookup            bool             `json:"idLookup"`
+	NameField           bool             `json:"nameField"`
+	Custom              bool             `json:"custom"`
+	Calculated          bool             `json:"calculated"`
+	CalculatedFormula   string           `json:"calculatedFormula"`
+	AutoNumber          bool             `json:"autoNumber"`
+	DefaultedOnCreate   bool             `json:"defaultedOnCreate"`
+	DefaultValue        interface{}      `json:"defaultValue"`
+	Filterable          bool             `json:"filterable"`
+	Sortable            bool             `json:"sortable"`
+	Groupable           bool             `json:"groupable"`
+	CaseSensitive       bool             `json:"caseSensitive"`
+	HTMLFormatted       bool             `json:"htmlFormatted"`
+	DependentPicklist   bool             `json:"dependentPicklist"`
+	ControllerName      string           `json:"controllerName"`
+	RestrictedPicklist  bool             `json:"restrictedPicklist"`
+	PicklistValues      []*PicklistValue `json:"picklistValues"`
+	ReferenceTo         []string         `json:"referenceTo"`
+	RelationshipName    string           `json:"relationshipName"`
+	RelationshipOrder   int              `json:"relationshipOrder"`
+	CascadeDelete       bool             `json:"cascadeDelete"`
+	RestrictedDelete    bool             `json:"restrictedDelete"`
+	InlineHelpText      string           `json:"inlineHelpText"`
+	DeprecatedAndHidden bool             `json:"deprecatedAndHidden"`
+}
+
+// PicklistValue is an entry of a picklist field.
+type PicklistValue struct {
+	Active       bool   `json:"active"`
+	DefaultValue bool   `json:"defaultValue"`
+	Label        string `json:"label"`
+	Value        string `json:"value"`
+	ValidFor     string `json:"validFor"`
+}
+
+// ChildRelationship is a relationship from another Salesforce Object to the described object.
+type ChildRelationship struct {
+	ChildSObject        string `json:"childSObject"`
+	Field               string `json:"field"`
+	RelationshipName    string `json:"relationshipName"`
+	CascadeDelete       bool   `json:"cascadeDelete"`
+	RestrictedDelete    bool   `json:"restrictedDelete"`
+	DeprecatedAndHidden bool   `json:"deprecatedAndHidden"`
+}
+
+// RecordTypeInfo is a record type available for the described object.
+type RecordTypeInfo struct {
+	Name                     string            `json:"name"`
+	DeveloperName            string            `json:"developerName"`
+	RecordTypeID             string            `json:"recordTypeId"`
+	Active                   bool              `json:"active"`
+	Available                bool              `json:"available"`
+	DefaultRecordTypeMapping bool              `json:"defaultRecordTypeMapping"`
+	Master                   bool              `json:"master"`
+	URLs                     map[string]string `json:"urls"`
+}
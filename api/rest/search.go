@@ -0,0 +1,128 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+const (
+	searchPath              = "/services/data/%s/search/"
+	parameterizedSearchPath = "/services/data/%s/parameterizedSearch/"
+)
+
+// SearchInput stores the input for executing a SOSL search.
+type SearchInput struct {
+	Search string
+}
+
+// SearchOutput stores the output after executing a search.
+type SearchOutput struct {
+	Result *SearchResult
+}
+
+// Search executes the SOSL search using the Salesforce API.
+func (c *Client) Search(input *SearchInput) (*SearchOutput, error) {
+	// validate parameters
+	if input.Search == "" {
+		return nil, errors.New("search string is required")
+	}
+
+	var result SearchResult
+	req := c.newRequest(&request.Operation{
+		Method:   http.MethodGet,
+		APIPath:  fmt.Sprintf(searchPath, c.sess.APIVersion),
+		RawQuery: "q=" + url.QueryEscape(input.Search),
+	}, request.JSONResult, &result, http.StatusOK)
+	return &SearchOutput{&result}, req.Send()
+}
+
+// SearchSObject specifies a SObject to search and the fields to return. Where and Limit
+// filter the records of the SObject.
+type SearchSObject struct {
+	Name   string   `json:"name"`
+	Fields []string `json:"fields,omitempty"`
+	Where  string   `json:"where,omitempty"`
+	Limit  int      `json:"limit,omitempty"`
+}
+
+// ParameterizedSearchInput stores the input for executing a parameterized search. In is the
+// scope of fields to search (ALL, NAME, EMAIL, PHONE or SIDEBAR). Fields are returned for
+// each SObject unless overridden by the SObject fields. Spell correction is enabled unless
+// DisableSpellCorrection is true.
+type ParameterizedSearchInput struct {
+	Search                 string
+	In                     string
+	SObjects               []*SearchSObject
+	Fields                 []string
+	OverallLimit           int
+	DefaultLimit           int
+	DisableSpellCorrection bool
+}
+
+// ParameterizedSearchOutput stores the output after executing a parameterized search.
+type ParameterizedSearchOutput struct {
+	Result *SearchResult
+}
+
+// ParameterizedSearch executes a search for the text using the Salesforce API, without
+// requiring a SOSL statement.
+func (c *Client) ParameterizedSearch(input *ParameterizedSearchInput) (*ParameterizedSearchOutput, error) {
+	// validate parameters
+	if input.Search == "" {
+		return nil, errors.New("search string is required")
+	}
+	for _, s := range input.SObjects {
+		if s == nil || isInvalidFieldName(s.Name) {
+			return nil, errors.New("invalid sobject name")
+		}
+		if err := ValidateFields(s.Fields); err != nil {
+			return nil, err
+		}
+		if s.Limit < 0 {
+			return nil, errors.New("limit cannot be negative")
+		}
+	}
+	if err := ValidateFields(input.Fields); err != nil {
+		return nil, err
+	}
+	if input.OverallLimit < 0 || input.DefaultLimit < 0 {
+		return nil, errors.New("limit cannot be negative")
+	}
+
+	body := map[string]interface{}{"q": input.Search}
+	if input.In != "" {
+		body["in"] = input.In
+	}
+	if len(input.SObjects) > 0 {
+		body["sobjects"] = input.SObjects
+	}
+	if len(input.Fields) > 0 {
+		body["fields"] = input.Fields
+	}
+	if input.OverallLimit > 0 {
+		body["overallLimit"] = input.OverallLimit
+	}
+	if input.DefaultLimit > 0 {
+		body["defaultLimit"] = input.DefaultLimit
+	}
+	if input.DisableSpellCorrection {
+		body["spellCorrection"] = false
+	}
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(body); err != nil {
+		return nil, fmt.Errorf("couldn't marshal search: %v", err)
+	}
+	var result SearchResult
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPost,
+		APIPath: fmt.Sprintf(parameterizedSearchPath, c.sess.APIVersion),
+		Body:    buf,
+	}, request.JSONResult, &result, http.StatusOK)
+	return &ParameterizedSearchOutput{&result}, req.Send()
+}
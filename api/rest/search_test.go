@@ -0,0 +1,132 @@
+package restapi
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+var searchResult = &SearchResult{SearchRecords: []SObject{
+	{"attributes": map[string]interface{}{"type": "Account"}, "Id": "001"},
+	{"attributes": map[string]interface{}{"type": "Contact"}, "Id": "003"},
+	{"attributes": map[string]interface{}{"type": "Account"}, "Id": "002"},
+}}
+
+func TestSearch(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	sosl := "FIND {Acme} IN NAME FIELDS RETURNING Account(Id), Contact(Id)"
+	tests := []struct {
+		search       string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", 0, 0, "search string is required"},
+		{sosl, 200, 1, ""},
+		{sosl, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/search", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: url.Values{"q": []string{test.search}}}, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.Search(&SearchInput{Search: test.search})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*SearchOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, searchResult, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: searchResult}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestParameterizedSearch(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		input        *ParameterizedSearchInput
+		body         map[string]interface{}
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{&ParameterizedSearchInput{}, nil, 0, 0, "search string is required"},
+		{&ParameterizedSearchInput{Search: "Acme", SObjects: []*SearchSObject{{Name: "1a"}}}, nil, 0, 0,
+			"invalid sobject name"},
+		{&ParameterizedSearchInput{Search: "Acme", SObjects: []*SearchSObject{nil}}, nil, 0, 0,
+			"invalid sobject name"},
+		{&ParameterizedSearchInput{Search: "Acme", Fields: []string{"Id", ""}}, nil, 0, 0, "invalid field list"},
+		{&ParameterizedSearchInput{Search: "Acme", OverallLimit: -1}, nil, 0, 0, "limit cannot be negative"},
+		{&ParameterizedSearchInput{Search: "Acme"}, map[string]interface{}{"q": "Acme"}, 200, 1, ""},
+		{&ParameterizedSearchInput{
+			Search: "Acme",
+			In:     "NAME",
+			SObjects: []*SearchSObject{
+				{Name: "Account", Fields: []string{"Id", "Name"}, Where: "Type = 'Customer'", Limit: 5},
+				{Name: "Contact"},
+			},
+			Fields:                 []string{"Id"},
+			OverallLimit:           20,
+			DefaultLimit:           10,
+			DisableSpellCorrection: true,
+		}, map[string]interface{}{
+			"q":  "Acme",
+			"in": "NAME",
+			"sobjects": []interface{}{
+				map[string]interface{}{"name": "Account", "fields": []string{"Id", "Name"},
+					"where": "Type = 'Customer'", "limit": 5},
+				map[string]interface{}{"name": "Contact"},
+			},
+			"fields":          []string{"Id"},
+			"overallLimit":    20,
+			"defaultLimit":    10,
+			"spellCorrection": false,
+		}, 200, 1, ""},
+		{&ParameterizedSearchInput{Search: "Acme"}, map[string]interface{}{"q": "Acme"}, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/parameterizedSearch", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.PathValidator{Path: path}, postMethodValidator,
+			&testserver.JSONBodyValidator{Body: test.body}}
+
+		requestFunc := func() (interface{}, error) {
+			return client.ParameterizedSearch(test.input)
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*ParameterizedSearchOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, searchResult, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: searchResult}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestSearchResultByType(t *testing.T) {
+	assert.Equal(t, map[string][]SObject{
+		"Account": {searchResult.SearchRecords[0], searchResult.SearchRecords[2]},
+		"Contact": {searchResult.SearchRecords[1]},
+	}, searchResult.ByType())
+	assert.Empty(t, (&SearchResult{}).ByType())
+}
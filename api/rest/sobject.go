@@ -304,6 +304,12 @@ func getLastSObjectInPath(s SObject, dottedField string, createIfNotExists, repl
 	return sobj, nil
 }
 
+// IsValidFieldName returns true if the name is a valid SObject or field name (e.g. Account,
+// Custom_Field__c).
+func IsValidFieldName(name string) bool {
+	return !isInvalidFieldName(name)
+}
+
 func isInvalidFieldName(fieldName string) bool {
 	// allow field to end with __c/__r for custom fields/relationships
 	fieldName = strings.TrimSuffix(fieldName, "__c")
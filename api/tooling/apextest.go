@@ -0,0 +1,350 @@
+package toolingapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+const (
+	runTestsAsynchronousPath = "/services/data/%s/tooling/runTestsAsynchronous/"
+	defaultPollInterval      = 5 * time.Second
+)
+
+// test levels of a test run
+const (
+	TestLevelRunSpecifiedTests = "RunSpecifiedTests"
+	TestLevelRunLocalTests     = "RunLocalTests"
+	TestLevelRunAllTestsInOrg  = "RunAllTestsInOrg"
+)
+
+var idRE = regexp.MustCompile(`^[a-zA-Z0-9]{15}([a-zA-Z0-9]{3})?$`)
+
+// RunTestsAsynchronousInput stores the input for enqueuing Apex tests. The test classes and
+// suites are run with the RunSpecifiedTests test level. Otherwise, the test level must be
+// RunLocalTests or RunAllTestsInOrg.
+type RunTestsAsynchronousInput struct {
+	ClassNames []string
+	SuiteNames []string
+	TestLevel  string
+}
+
+// RunTestsAsynchronousOutput stores the output after enqueuing Apex tests. JobID is the id of
+// the AsyncApexJob of the test run.
+type RunTestsAsynchronousOutput struct {
+	JobID string
+}
+
+// RunTestsAsynchronous enqueues the Apex tests using the Salesforce Tooling API. Use
+// WaitForTests to wait for the test run to complete.
+func (c *Client) RunTestsAsynchronous(input *RunTestsAsynchronousInput) (*RunTestsAsynchronousOutput, error) {
+	// validate parameters
+	testLevel := input.TestLevel
+	hasNames := len(input.ClassNames) > 0 || len(input.SuiteNames) > 0
+	switch {
+	case hasNames && testLevel == "":
+		testLevel = TestLevelRunSpecifiedTests
+	case hasNames && testLevel != TestLevelRunSpecifiedTests:
+		return nil, fmt.Errorf("test level must be %s when class or suite names are specified",
+			TestLevelRunSpecifiedTests)
+	case !hasNames && (testLevel == "" || testLevel == TestLevelRunSpecifiedTests):
+		return nil, errors.New("class names, suite names or test level is required")
+	}
+	for _, name := range append(append([]string{}, input.ClassNames...), input.SuiteNames...) {
+		if name == "" {
+			return nil, errors.New("class and suite names cannot be empty")
+		}
+	}
+
+	body := map[string]string{"testLevel": testLevel}
+	if len(input.ClassNames) > 0 {
+		body["classNames"] = strings.Join(input.ClassNames, ",")
+	}
+	if len(input.SuiteNames) > 0 {
+		body["suiteNames"] = strings.Join(input.SuiteNames, ",")
+	}
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(body); err != nil {
+		return nil, fmt.Errorf("couldn't marshal body: %v", err)
+	}
+	var jobID string
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPost,
+		APIPath: fmt.Sprintf(runTestsAsynchronousPath, c.sess.APIVersion),
+		Body:    buf,
+	}, request.JSONResult, &jobID, http.StatusOK)
+	err := req.Send()
+	return &RunTestsAsynchronousOutput{jobID}, err
+}
+
+// GetApexTestQueueItemsInput stores the input for retrieving the test classes of a test run.
+type GetApexTestQueueItemsInput struct {
+	JobID string
+}
+
+// GetApexTestQueueItemsOutput stores the output after retrieving the test classes of a test
+// run.
+type GetApexTestQueueItemsOutput struct {
+	QueueItems []*ApexTestQueueItem
+}
+
+// GetApexTestQueueItems retrieves the queued test classes of the test run using the
+// Salesforce Tooling API.
+func (c *Client) GetApexTestQueueItems(ctx context.Context, input *GetApexTestQueueItemsInput) (*GetApexTestQueueItemsOutput, error) {
+	// validate parameters
+	if !idRE.MatchString(input.JobID) {
+		return nil, errors.New("invalid job id")
+	}
+
+	var items []*ApexTestQueueItem
+	soql := "SELECT Id, ApexClassId, ApexClass.Name, Status, ExtendedStatus " +
+		"FROM ApexTestQueueItem WHERE ParentJobId = '" + input.JobID + "'"
+	if err := c.queryInto(ctx, soql, &items); err != nil {
+		return nil, err
+	}
+	return &GetApexTestQueueItemsOutput{items}, nil
+}
+
+// GetApexTestResultsInput stores the input for retrieving the test method results of a test
+// run.
+type GetApexTestResultsInput struct {
+	JobID string
+}
+
+// GetApexTestResultsOutput stores the output after retrieving the test method results of a
+// test run.
+type GetApexTestResultsOutput struct {
+	Results []*ApexTestResult
+}
+
+// GetApexTestResults retrieves the results of the test methods of the test run using the
+// Salesforce Tooling API.
+func (c *Client) GetApexTestResults(ctx context.Context, input *GetApexTestResultsInput) (*GetApexTestResultsOutput, error) {
+	// validate parameters
+	if !idRE.MatchString(input.JobID) {
+		return nil, errors.New("invalid job id")
+	}
+
+	var results []*ApexTestResult
+	soql := "SELECT Id, QueueItemId, ApexClassId, ApexClass.Name, MethodName, Outcome, " +
+		"Message, StackTrace, RunTime FROM ApexTestResult WHERE AsyncApexJobId = '" +
+		input.JobID + "' ORDER BY ApexClass.Name, MethodName"
+	if err := c.queryInto(ctx, soql, &results); err != nil {
+		return nil, err
+	}
+	return &GetApexTestResultsOutput{results}, nil
+}
+
+// WaitForTestsInput stores the input for waiting for a test run to complete. The test
+// classes are polled every poll interval, which defaults to 5 seconds.
+type WaitForTestsInput struct {
+	JobID        string
+	PollInterval time.Duration
+}
+
+// WaitForTestsOutput stores the output after a test run completed.
+type WaitForTestsOutput struct {
+	QueueItems []*ApexTestQueueItem
+	Results    []*ApexTestResult
+}
+
+// WaitForTests polls the test classes of the test run until every class is processed, then
+// retrieves the test method results. An error is returned if the test run does not exist, or
+// finished without test classes. Waiting stops if the context is done.
+func (c *Client) WaitForTests(ctx context.Context, input *WaitForTestsInput) (*WaitForTestsOutput, error) {
+	// validate parameters
+	if !idRE.MatchString(input.JobID) {
+		return nil, errors.New("invalid job id")
+	}
+	pollInterval := input.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+
+	for {
+		out, err := c.GetApexTestQueueItems(ctx, &GetApexTestQueueItemsInput{input.JobID})
+		if err != nil {
+			return nil, err
+		}
+		if len(out.QueueItems) > 0 && allProcessed(out.QueueItems) {
+			results, err := c.GetApexTestResults(ctx, &GetApexTestResultsInput{input.JobID})
+			if err != nil {
+				return nil, err
+			}
+			return &WaitForTestsOutput{out.QueueItems, results.Results}, nil
+		}
+		// the test classes may not be queued yet, stop if the test run has finished
+		if len(out.QueueItems) == 0 {
+			if err := c.checkTestRunQueued(ctx, input.JobID); err != nil {
+				return nil, err
+			}
+		}
+
+		// wait for next poll
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// GetCodeCoverageInput stores the input for retrieving the code coverage of Apex classes and
+// triggers. The coverage is limited to the classes and triggers with the names, and to the
+// classes and triggers covered by the test classes with the ids (e.g. the ApexClassId of the
+// queue items of a test run). The coverage of every class and trigger is retrieved if
+// neither is specified.
+type GetCodeCoverageInput struct {
+	Names        []string
+	TestClassIDs []string
+}
+
+// GetCodeCoverageOutput stores the output after retrieving the code coverage of Apex classes
+// and triggers.
+type GetCodeCoverageOutput struct {
+	Coverage []*ApexCodeCoverage
+}
+
+// Percent returns the percentage of lines covered across the classes and triggers. Returns
+// 0 if there are no lines.
+func (out *GetCodeCoverageOutput) Percent() float64 {
+	var covered, total int
+	for _, cov := range out.Coverage {
+		covered += cov.NumLinesCovered
+		total += cov.NumLinesCovered + cov.NumLinesUncovered
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(covered) / float64(total) * 100
+}
+
+// GetCodeCoverage retrieves the aggregate code coverage of the Apex classes and triggers
+// from every test run using the Salesforce Tooling API.
+func (c *Client) GetCodeCoverage(ctx context.Context, input *GetCodeCoverageInput) (*GetCodeCoverageOutput, error) {
+	// validate parameters
+	names := make([]string, len(input.Names))
+	for i, name := range input.Names {
+		if name == "" {
+			return nil, errors.New("class and trigger names cannot be empty")
+		}
+		names[i] = quote(name)
+	}
+	testClassIDs := make([]string, len(input.TestClassIDs))
+	for i, id := range input.TestClassIDs {
+		if !idRE.MatchString(id) {
+			return nil, fmt.Errorf("invalid test class id %q", id)
+		}
+		testClassIDs[i] = quote(id)
+	}
+
+	var where []string
+	if len(names) > 0 {
+		where = append(where, "ApexClassOrTrigger.Name IN ("+strings.Join(names, ", ")+")")
+	}
+	if len(testClassIDs) > 0 {
+		// classes and triggers covered by the test classes
+		var covered []*struct {
+			ApexClassOrTriggerID string `json:"ApexClassOrTriggerId"`
+		}
+		soql := "SELECT ApexClassOrTriggerId FROM ApexCodeCoverage WHERE ApexTestClassId IN (" +
+			strings.Join(testClassIDs, ", ") + ")"
+		if err := c.queryInto(ctx, soql, &covered); err != nil {
+			return nil, err
+		}
+		if len(covered) == 0 {
+			return &GetCodeCoverageOutput{[]*ApexCodeCoverage{}}, nil
+		}
+		ids := make([]string, 0, len(covered))
+		seen := make(map[string]bool)
+		for _, cov := range covered {
+			if !seen[cov.ApexClassOrTriggerID] {
+				seen[cov.ApexClassOrTriggerID] = true
+				ids = append(ids, quote(cov.ApexClassOrTriggerID))
+			}
+		}
+		where = append(where, "ApexClassOrTriggerId IN ("+strings.Join(ids, ", ")+")")
+	}
+	soql := "SELECT ApexClassOrTriggerId, ApexClassOrTrigger.Name, NumLinesCovered, " +
+		"NumLinesUncovered FROM ApexCodeCoverageAggregate"
+	if len(where) > 0 {
+		soql += " WHERE " + strings.Join(where, " AND ")
+	}
+	soql += " ORDER BY ApexClassOrTrigger.Name"
+
+	var coverage []*ApexCodeCoverage
+	if err := c.queryInto(ctx, soql, &coverage); err != nil {
+		return nil, err
+	}
+	return &GetCodeCoverageOutput{coverage}, nil
+}
+
+// checkTestRunQueued returns an error if the test run does not exist, or has finished
+// without test classes.
+func (c *Client) checkTestRunQueued(ctx context.Context, jobID string) error {
+	var jobs []*struct {
+		Status string `json:"Status"`
+	}
+	soql := "SELECT Id, Status FROM AsyncApexJob WHERE Id = '" + jobID + "'"
+	if err := c.queryInto(ctx, soql, &jobs); err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		return fmt.Errorf("test run %s not found", jobID)
+	}
+	switch jobs[0].Status {
+	case "Completed", "Failed", "Aborted":
+		return fmt.Errorf("test run %s is %s, no tests ran", jobID, jobs[0].Status)
+	}
+	return nil
+}
+
+// allProcessed returns true if every test class has been processed.
+func allProcessed(items []*ApexTestQueueItem) bool {
+	for _, item := range items {
+		if !item.Processed() {
+			return false
+		}
+	}
+	return true
+}
+
+// queryInto executes the tooling SOQL query and unmarshals the records into v.
+func (c *Client) queryInto(ctx context.Context, soql string, v interface{}) error {
+	records, err := c.QueryAll(ctx, soql, 0)
+	if err != nil {
+		return err
+	}
+	if records == nil {
+		records = []restapi.SObject{}
+	}
+	return remarshal(records, v)
+}
+
+// remarshal unmarshals the json encoding of the SObjects (e.g. query records) into v.
+func remarshal(sobjs interface{}, v interface{}) error {
+	b, err := json.Marshal(sobjs)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal records: %v", err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to unmarshal records: %v", err)
+	}
+	return nil
+}
+
+// quote returns the string as a SOQL string literal.
+func quote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,333 @@
+package toolingapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+const jobID = "7071h00000ABCDE"
+
+func TestRunTestsAsynchronous(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		classNames   []string
+		suiteNames   []string
+		testLevel    string
+		wantBody     map[string]interface{}
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{nil, nil, "", nil, 0, 0, "class names, suite names or test level is required"},
+		{nil, nil, TestLevelRunSpecifiedTests, nil, 0, 0, "class names, suite names or test level is required"},
+		{[]string{"ATest"}, nil, TestLevelRunLocalTests, nil, 0, 0, "test level must be RunSpecifiedTests"},
+		{[]string{"ATest", ""}, nil, "", nil, 0, 0, "class and suite names cannot be empty"},
+		{[]string{"ATest", "BTest"}, nil, "", map[string]interface{}{"classNames": "ATest,BTest",
+			"testLevel": TestLevelRunSpecifiedTests}, 200, 1, ""},
+		{nil, []string{"Smoke"}, TestLevelRunSpecifiedTests, map[string]interface{}{"suiteNames": "Smoke",
+			"testLevel": TestLevelRunSpecifiedTests}, 200, 1, ""},
+		{nil, nil, TestLevelRunLocalTests, map[string]interface{}{"testLevel": TestLevelRunLocalTests},
+			200, 1, ""},
+		{nil, nil, TestLevelRunLocalTests, map[string]interface{}{"testLevel": TestLevelRunLocalTests},
+			400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/runTestsAsynchronous", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.JSONBodyValidator{Body: test.wantBody},
+			&testserver.PathValidator{Path: path}, postMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.RunTestsAsynchronous(&RunTestsAsynchronousInput{
+				ClassNames: test.classNames,
+				SuiteNames: test.suiteNames,
+				TestLevel:  test.testLevel,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*RunTestsAsynchronousOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, jobID, out.JobID, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: jobID}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+// queryPage returns a handler for a tooling query result with the records.
+func queryPage(records ...restapi.SObject) testserver.ResponseHandler {
+	if records == nil {
+		records = []restapi.SObject{}
+	}
+	return &testserver.JSONResponseHandler{
+		StatusCode: http.StatusOK,
+		Body:       &restapi.QueryResult{Done: true, TotalSize: len(records), Records: records},
+	}
+}
+
+func queueItem(id, status string) restapi.SObject {
+	return restapi.SObject{"Id": id, "ApexClassId": "01p" + id, "Status": status,
+		"ApexClass": map[string]interface{}{"Name": "Class" + id}}
+}
+
+func asyncJob(status string) testserver.ResponseHandler {
+	return queryPage(restapi.SObject{"Id": jobID, "Status": status})
+}
+
+func TestWaitForTests(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	result := restapi.SObject{"Id": "07M1", "QueueItemId": "1", "ApexClassId": "01p1",
+		"ApexClass": map[string]interface{}{"Name": "Class1"}, "MethodName": "testA",
+		"Outcome": "Fail", "Message": "Assertion Failed", "StackTrace": "Class.Class1.testA: line 5",
+		"RunTime": 12}
+	wantResult := &ApexTestResult{ID: "07M1", QueueItemID: "1", ApexClassID: "01p1",
+		ApexClass: &ApexClassRef{"Class1"}, MethodName: "testA", Outcome: "Fail",
+		Message: "Assertion Failed", StackTrace: "Class.Class1.testA: line 5", RunTime: 12}
+	errorPage := &testserver.JSONResponseHandler{
+		StatusCode: http.StatusBadRequest,
+		Body:       []interface{}{genericErr},
+	}
+
+	tests := []struct {
+		jobID        string
+		pages        []testserver.ResponseHandler
+		requestCount int
+		errSnippet   string
+		wantItems    int
+		wantResults  []*ApexTestResult
+	}{
+		{"", nil, 0, "invalid job id", 0, nil},
+		{"707' OR Id != '", nil, 0, "invalid job id", 0, nil},
+		{jobID, []testserver.ResponseHandler{queryPage(queueItem("1", "Completed")), queryPage(result)},
+			2, "", 1, []*ApexTestResult{wantResult}},
+		{jobID, []testserver.ResponseHandler{queryPage(), asyncJob("Queued"),
+			queryPage(queueItem("1", "Queued"), queueItem("2", "Processing")),
+			queryPage(queueItem("1", "Completed"), queueItem("2", "Processing")),
+			queryPage(queueItem("1", "Completed"), queueItem("2", "Aborted")), queryPage(result)},
+			6, "", 2, []*ApexTestResult{wantResult}},
+		{jobID, []testserver.ResponseHandler{queryPage(), asyncJob("Completed")},
+			2, "test run " + jobID + " is Completed, no tests ran", 0, nil},
+		{jobID, []testserver.ResponseHandler{queryPage(), queryPage()},
+			2, "test run " + jobID + " not found", 0, nil},
+		{jobID, []testserver.ResponseHandler{queryPage(queueItem("1", "Queued")), errorPage},
+			2, "GENERIC_ERROR", 0, nil},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		pages := test.pages
+		if pages == nil {
+			pages = []testserver.ResponseHandler{errorPage}
+		}
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: pages},
+			authTokenValidator, getMethodValidator,
+			&testserver.PathValidator{Path: fmt.Sprintf("/services/data/%s/tooling/query", apiVersion)})
+		server.RequestCount = 0
+
+		out, err := client.WaitForTests(context.Background(), &WaitForTestsInput{
+			JobID:        test.jobID,
+			PollInterval: time.Millisecond,
+		})
+		assert.Equal(t, test.requestCount, server.RequestCount, assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		if assert.Nil(t, err, assertMsg) {
+			assert.Len(t, out.QueueItems, test.wantItems, assertMsg)
+			assert.Equal(t, test.wantResults, out.Results, assertMsg)
+		}
+	}
+}
+
+func TestWaitForTestsCanceled(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, "canceled",
+		queryPage(queueItem("1", "Queued")))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.WaitForTests(ctx, &WaitForTestsInput{JobID: jobID, PollInterval: time.Hour})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1, server.RequestCount)
+}
+
+func TestGetCodeCoverage(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	coverage := restapi.SObject{"ApexClassOrTriggerId": "01p1", "NumLinesCovered": 3,
+		"NumLinesUncovered": 1, "ApexClassOrTrigger": map[string]interface{}{"Name": "O'Brien"}}
+	tests := []struct {
+		names        []string
+		where        string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{[]string{""}, "", 0, 0, "class and trigger names cannot be empty"},
+		{nil, "", 200, 1, ""},
+		{[]string{"O'Brien", "Trigger"}, ` WHERE ApexClassOrTrigger.Name IN ('O\'Brien', 'Trigger')`,
+			200, 1, ""},
+		{nil, "", 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/query", apiVersion)
+		soql := "SELECT ApexClassOrTriggerId, ApexClassOrTrigger.Name, NumLinesCovered, " +
+			"NumLinesUncovered FROM ApexCodeCoverageAggregate" + test.where +
+			" ORDER BY ApexClassOrTrigger.Name"
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: url.Values{"q": {soql}}}, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.GetCodeCoverage(context.Background(), &GetCodeCoverageInput{Names: test.names})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*GetCodeCoverageOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, []*ApexCodeCoverage{{ApexClassOrTriggerID: "01p1",
+					ApexClassOrTrigger: &ApexClassRef{"O'Brien"}, NumLinesCovered: 3,
+					NumLinesUncovered: 1}}, out.Coverage, assertMsg)
+				assert.Equal(t, 75.0, out.Percent(), assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode,
+			Body: &restapi.QueryResult{Done: true, TotalSize: 1, Records: []restapi.SObject{coverage}}}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestCodeCoveragePercent(t *testing.T) {
+	out := &GetCodeCoverageOutput{Coverage: []*ApexCodeCoverage{
+		{NumLinesCovered: 9, NumLinesUncovered: 1},
+		{NumLinesCovered: 0, NumLinesUncovered: 10},
+		{},
+	}}
+	assert.Equal(t, 45.0, out.Percent())
+	assert.Equal(t, 90.0, out.Coverage[0].Percent())
+	assert.Equal(t, 0.0, out.Coverage[2].Percent())
+	assert.Equal(t, 0.0, (&GetCodeCoverageOutput{}).Percent())
+}
+
+func TestApexTestQueueItemFailed(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{"Queued", false},
+		{"Processing", false},
+		{"Completed", false},
+		{"Failed", true},
+		{"Aborted", true},
+	}
+
+	for _, test := range tests {
+		item := &ApexTestQueueItem{Status: test.status}
+		assert.Equal(t, test.want, item.Failed(), test.status)
+	}
+	assert.Equal(t, "", (&ApexTestQueueItem{}).ClassName())
+	assert.Equal(t, "MyTest", (&ApexTestQueueItem{ApexClass: &ApexClassRef{"MyTest"}}).ClassName())
+}
+
+// soqlRecorder records the SOQL query of each request.
+type soqlRecorder struct {
+	queries []string
+}
+
+// Validate implements the RequestValidator interface.
+func (r *soqlRecorder) Validate(req *http.Request) error {
+	r.queries = append(r.queries, req.URL.Query().Get("q"))
+	return nil
+}
+
+func TestGetCodeCoverageForTestClasses(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	coverage := restapi.SObject{"ApexClassOrTriggerId": "01p000000000001", "NumLinesCovered": 1,
+		"NumLinesUncovered": 1, "ApexClassOrTrigger": map[string]interface{}{"Name": "Class1"}}
+	covered := func(id string) restapi.SObject {
+		return restapi.SObject{"ApexClassOrTriggerId": id}
+	}
+	coverageSOQL := "SELECT ApexClassOrTriggerId FROM ApexCodeCoverage WHERE ApexTestClassId IN " +
+		"('01p000000000T01', '01p000000000T02')"
+	aggregateSOQL := "SELECT ApexClassOrTriggerId, ApexClassOrTrigger.Name, NumLinesCovered, " +
+		"NumLinesUncovered FROM ApexCodeCoverageAggregate WHERE "
+
+	tests := []struct {
+		names        []string
+		testClassIDs []string
+		pages        []testserver.ResponseHandler
+		wantQueries  []string
+		errSnippet   string
+		wantCoverage int
+	}{
+		{nil, []string{"01p' OR Id != '"}, nil, nil, "invalid test class id", 0},
+		{nil, []string{"01p000000000T01", "01p000000000T02"},
+			[]testserver.ResponseHandler{queryPage(covered("01p000000000001"), covered("01q000000000002"),
+				covered("01p000000000001")), queryPage(coverage)},
+			[]string{coverageSOQL, aggregateSOQL +
+				"ApexClassOrTriggerId IN ('01p000000000001', '01q000000000002') ORDER BY ApexClassOrTrigger.Name"},
+			"", 1},
+		{[]string{"Class1"}, []string{"01p000000000T01", "01p000000000T02"},
+			[]testserver.ResponseHandler{queryPage(covered("01p000000000001")), queryPage(coverage)},
+			[]string{coverageSOQL, aggregateSOQL + "ApexClassOrTrigger.Name IN ('Class1') AND " +
+				"ApexClassOrTriggerId IN ('01p000000000001') ORDER BY ApexClassOrTrigger.Name"},
+			"", 1},
+		{nil, []string{"01p000000000T01", "01p000000000T02"},
+			[]testserver.ResponseHandler{queryPage()}, []string{coverageSOQL}, "", 0},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		pages := test.pages
+		if pages == nil {
+			pages = []testserver.ResponseHandler{queryPage()}
+		}
+		recorder := &soqlRecorder{}
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: pages}, authTokenValidator, recorder)
+
+		out, err := client.GetCodeCoverage(context.Background(), &GetCodeCoverageInput{
+			Names:        test.names,
+			TestClassIDs: test.testClassIDs,
+		})
+		assert.Equal(t, test.wantQueries, recorder.queries, assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		if assert.Nil(t, err, assertMsg) {
+			assert.Len(t, out.Coverage, test.wantCoverage, assertMsg)
+		}
+	}
+}
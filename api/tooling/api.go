@@ -0,0 +1,238 @@
+package toolingapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+const (
+	sObjectPath = "/services/data/%s/tooling/sobjects/"
+	queryPath   = "/services/data/%s/tooling/query/"
+)
+
+// CreateSObjectInput stores the input for creating a tooling SObject (e.g. ApexClass,
+// TraceFlag).
+type CreateSObjectInput struct {
+	SObjectName string
+	SObject     restapi.SObject
+}
+
+// CreateSObjectOutput stores the output after creating a tooling SObject.
+type CreateSObjectOutput struct {
+	Result *restapi.UpsertResult
+}
+
+// CreateSObject creates the tooling SObject using the Salesforce Tooling API.
+func (c *Client) CreateSObject(input *CreateSObjectInput) (*CreateSObjectOutput, error) {
+	// validate parameters
+	if !restapi.IsValidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+	if len(input.SObject) == 0 {
+		return nil, errors.New("sobject value is required")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(input.SObject); err != nil {
+		return nil, fmt.Errorf("couldn't marshal sobject: %v", err)
+	}
+	var result restapi.UpsertResult
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPost,
+		APIPath: c.sObjectPath(input.SObjectName),
+		Body:    buf,
+	}, request.JSONResult, &result, http.StatusCreated)
+	return &CreateSObjectOutput{&result}, req.Send()
+}
+
+// GetSObjectInput stores the input for retrieving a tooling SObject by ID.
+type GetSObjectInput struct {
+	SObjectName string
+	SObjectID   string
+	Fields      []string
+}
+
+// GetSObjectOutput stores the output after retrieving a tooling SObject.
+type GetSObjectOutput struct {
+	SObject restapi.SObject
+}
+
+// GetSObject retrieves the tooling SObject from the Salesforce Tooling API.
+func (c *Client) GetSObject(input *GetSObjectInput) (*GetSObjectOutput, error) {
+	// validate parameters
+	if err := validateSObjectID(input.SObjectName, input.SObjectID); err != nil {
+		return nil, err
+	}
+	if err := restapi.ValidateFields(input.Fields); err != nil {
+		return nil, err
+	}
+
+	var sobj restapi.SObject
+	req := c.newRequest(&request.Operation{
+		Method:   http.MethodGet,
+		APIPath:  c.sObjectPath(input.SObjectName, input.SObjectID),
+		RawQuery: restapi.FieldsQuery(input.Fields),
+	}, request.JSONResult, &sobj, http.StatusOK)
+	err := req.Send()
+	return &GetSObjectOutput{sobj}, err
+}
+
+// UpdateSObjectInput stores the input for updating a tooling SObject by ID.
+type UpdateSObjectInput struct {
+	SObjectName string
+	SObjectID   string
+	SObject     restapi.SObject
+}
+
+// UpdateSObjectOutput stores the output after updating a tooling SObject.
+type UpdateSObjectOutput struct {
+}
+
+// UpdateSObject updates the tooling SObject using the Salesforce Tooling API.
+func (c *Client) UpdateSObject(input *UpdateSObjectInput) (*UpdateSObjectOutput, error) {
+	// validate parameters
+	if err := validateSObjectID(input.SObjectName, input.SObjectID); err != nil {
+		return nil, err
+	}
+	if len(input.SObject) == 0 {
+		return nil, errors.New("sobject value is required")
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(input.SObject); err != nil {
+		return nil, fmt.Errorf("couldn't marshal sobject: %v", err)
+	}
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodPatch,
+		APIPath: c.sObjectPath(input.SObjectName, input.SObjectID),
+		Body:    buf,
+	}, request.JSONResult, nil, http.StatusNoContent)
+	return &UpdateSObjectOutput{}, req.Send()
+}
+
+// DeleteSObjectInput stores the input for deleting a tooling SObject.
+type DeleteSObjectInput struct {
+	SObjectName string
+	SObjectID   string
+}
+
+// DeleteSObjectOutput stores the output after deleting a tooling SObject.
+type DeleteSObjectOutput struct{}
+
+// DeleteSObject deletes the tooling SObject using the Salesforce Tooling API.
+func (c *Client) DeleteSObject(input *DeleteSObjectInput) (*DeleteSObjectOutput, error) {
+	// validate parameters
+	if err := validateSObjectID(input.SObjectName, input.SObjectID); err != nil {
+		return nil, err
+	}
+
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodDelete,
+		APIPath: c.sObjectPath(input.SObjectName, input.SObjectID),
+	}, request.JSONResult, nil, http.StatusNoContent)
+	return &DeleteSObjectOutput{}, req.Send()
+}
+
+// QueryInput stores the input for querying tooling SObjects.
+type QueryInput struct {
+	Query string
+}
+
+// QueryOutput stores the output after querying tooling SObjects.
+type QueryOutput struct {
+	Result *restapi.QueryResult
+}
+
+// Query executes a SOQL query against the tooling SObjects using the Salesforce Tooling
+// API. The remaining records are retrieved using QueryMore.
+func (c *Client) Query(input *QueryInput) (*QueryOutput, error) {
+	// validate parameters
+	if input.Query == "" {
+		return nil, errors.New("query string is required")
+	}
+
+	var queryResult restapi.QueryResult
+	req := c.newRequest(&request.Operation{
+		Method:   http.MethodGet,
+		APIPath:  fmt.Sprintf(queryPath, c.sess.APIVersion),
+		RawQuery: "q=" + url.QueryEscape(input.Query),
+	}, request.JSONResult, &queryResult, http.StatusOK)
+	return &QueryOutput{&queryResult}, req.Send()
+}
+
+// QueryMoreInput stores the input for querying the next batch of records.
+type QueryMoreInput struct {
+	NextRecordsURL string
+}
+
+// QueryMoreOutput stores the output after querying the next batch of records.
+type QueryMoreOutput struct {
+	Result *restapi.QueryResult
+}
+
+// QueryMore retrieves the next batch of tooling query records from the Salesforce Tooling
+// API.
+func (c *Client) QueryMore(input *QueryMoreInput) (*QueryMoreOutput, error) {
+	// validate parameters
+	if input.NextRecordsURL == "" {
+		return nil, errors.New("missing next records url")
+	}
+
+	var queryResult restapi.QueryResult
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodGet,
+		APIPath: input.NextRecordsURL,
+	}, request.JSONResult, &queryResult, http.StatusOK)
+	return &QueryMoreOutput{&queryResult}, req.Send()
+}
+
+// QueryIterator returns an iterator for the records of the tooling SOQL query. No request
+// is made until the first call to Next.
+func (c *Client) QueryIterator(ctx context.Context, soql string) *restapi.QueryIterator {
+	return restapi.NewQueryIterator(ctx, func() (*restapi.QueryResult, error) {
+		out, err := c.Query(&QueryInput{Query: soql})
+		if err != nil {
+			return nil, err
+		}
+		return out.Result, nil
+	}, func(nextRecordsURL string) (*restapi.QueryResult, error) {
+		out, err := c.QueryMore(&QueryMoreInput{NextRecordsURL: nextRecordsURL})
+		if err != nil {
+			return nil, err
+		}
+		return out.Result, nil
+	})
+}
+
+// QueryAll executes the tooling SOQL query and returns the records from every batch. An
+// error is returned if the query has more than maxRecords records. A maxRecords of zero or
+// less means there is no limit.
+func (c *Client) QueryAll(ctx context.Context, soql string, maxRecords int) ([]restapi.SObject, error) {
+	return c.QueryIterator(ctx, soql).Collect(maxRecords)
+}
+
+// sObjectPath returns the api path for the tooling sobject resource joined with the
+// elements.
+func (c *Client) sObjectPath(elem ...string) string {
+	return path.Join(append([]string{fmt.Sprintf(sObjectPath, c.sess.APIVersion)}, elem...)...)
+}
+
+// validateSObjectID returns an error if the sobject name or id is invalid.
+func validateSObjectID(sobjectName, sobjectID string) error {
+	if !restapi.IsValidFieldName(sobjectName) {
+		return errors.New("invalid sobject name")
+	}
+	if sobjectID == "" {
+		return errors.New("sobject id is required")
+	}
+	return nil
+}
@@ -0,0 +1,411 @@
+package toolingapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/Laugusti/go-sforce/sforce/credentials"
+	"github.com/Laugusti/go-sforce/sforce/session"
+	"github.com/Laugusti/go-sforce/sforce/sforceerr"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	accessToken = "MOCK_TOKEN"
+	apiVersion  = "mock"
+)
+
+var (
+	// api error
+	genericErr = sforceerr.APIError{Message: "Generic API error", ErrorCode: "GENERIC_ERROR"}
+
+	// request validators
+	jsonContentTypeValidator = &testserver.HeaderValidator{Key: "Content-Type", Value: "application/json"}
+	authTokenValidator       = &testserver.HeaderValidator{Key: "Authorization", Value: "Bearer " + accessToken}
+	emptyQueryValidator      = &testserver.QueryValidator{Query: url.Values{}}
+	emptyBodyValidator       = &testserver.JSONBodyValidator{Body: nil}
+	getMethodValidator       = &testserver.MethodValidator{Method: http.MethodGet}
+	postMethodValidator      = &testserver.MethodValidator{Method: http.MethodPost}
+	patchMethodValidator     = &testserver.MethodValidator{Method: http.MethodPatch}
+	deleteMethodValidator    = &testserver.MethodValidator{Method: http.MethodDelete}
+)
+
+func createClientAndServer(t *testing.T) (*Client, *testserver.Server) {
+	// start server
+	s := testserver.New(t)
+
+	// create session and login
+	s.HandlerFunc = testserver.StaticJSONHandlerFunc(t, http.StatusOK,
+		session.RequestToken{
+			AccessToken: accessToken,
+			InstanceURL: s.URL(),
+		})
+	sess := session.Must(session.New(
+		s.URL(),
+		apiVersion,
+		credentials.New("user", "pass", "cid", "csecret"),
+	))
+	if err := sess.Login(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, s.RequestCount, "expected single request (login)")
+	s.RequestCount = 0 // reset counter
+
+	// create client
+	client := &Client{sess}
+
+	return client, s
+}
+
+func TestCreateSObject(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	sobj := restapi.SObject{"Name": "Hello", "Body": "public class Hello {}"}
+	tests := []struct {
+		objectType   string
+		object       restapi.SObject
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", sobj, 0, 0, "invalid sobject name"},
+		{"ApexClass", nil, 0, 0, "sobject value is required"},
+		{"ApexClass", sobj, 201, 1, ""},
+		{"ApexClass", sobj, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/sobjects/%s", apiVersion, test.objectType)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.JSONBodyValidator{Body: test.object},
+			&testserver.PathValidator{Path: path}, postMethodValidator}
+
+		want := &restapi.UpsertResult{ID: "01p", Success: true}
+		requestFunc := func() (interface{}, error) {
+			return client.CreateSObject(&CreateSObjectInput{
+				SObjectName: test.objectType,
+				SObject:     test.object,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*CreateSObjectOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, want, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: want}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestGetSObject(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		objectType   string
+		objectID     string
+		fields       []string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", "01p", nil, 0, 0, "invalid sobject name"},
+		{"ApexClass", "", nil, 0, 0, "sobject id is required"},
+		{"ApexClass", "01p", []string{"Name", "1"}, 0, 0, "invalid field list"},
+		{"ApexClass", "01p", nil, 200, 1, ""},
+		{"ApexClass", "01p", []string{"Name", "Body"}, 200, 1, ""},
+		{"ApexClass", "01p", nil, 404, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/sobjects/%s/%s", apiVersion,
+			test.objectType, test.objectID)
+		query := url.Values{}
+		if len(test.fields) > 0 {
+			query.Set("fields", fmt.Sprintf("%s,%s", test.fields[0], test.fields[1]))
+		}
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: query}, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		want := restapi.SObject{"Id": "01p", "Name": "Hello"}
+		requestFunc := func() (interface{}, error) {
+			return client.GetSObject(&GetSObjectInput{
+				SObjectName: test.objectType,
+				SObjectID:   test.objectID,
+				Fields:      test.fields,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*GetSObjectOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, want, out.SObject, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: want}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestUpdateSObject(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	sobj := restapi.SObject{"LogType": "USER_DEBUG"}
+	tests := []struct {
+		objectType   string
+		objectID     string
+		object       restapi.SObject
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", "7tf", sobj, 0, 0, "invalid sobject name"},
+		{"TraceFlag", "", sobj, 0, 0, "sobject id is required"},
+		{"TraceFlag", "7tf", nil, 0, 0, "sobject value is required"},
+		{"TraceFlag", "7tf", sobj, 204, 1, ""},
+		{"TraceFlag", "7tf", sobj, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/sobjects/%s/%s", apiVersion,
+			test.objectType, test.objectID)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, &testserver.JSONBodyValidator{Body: test.object},
+			&testserver.PathValidator{Path: path}, patchMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.UpdateSObject(&UpdateSObjectInput{
+				SObjectName: test.objectType,
+				SObjectID:   test.objectID,
+				SObject:     test.object,
+			})
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, nil,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestDeleteSObject(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		objectType   string
+		objectID     string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", "7tf", 0, 0, "invalid sobject name"},
+		{"TraceFlag", "", 0, 0, "sobject id is required"},
+		{"TraceFlag", "7tf", 204, 1, ""},
+		{"TraceFlag", "7tf", 404, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/sobjects/%s/%s", apiVersion,
+			test.objectType, test.objectID)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, deleteMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.DeleteSObject(&DeleteSObjectInput{
+				SObjectName: test.objectType,
+				SObjectID:   test.objectID,
+			})
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, nil,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	soql := "SELECT Id, Name FROM ApexClass"
+	tests := []struct {
+		query        string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", 0, 0, "query string is required"},
+		{soql, 200, 1, ""},
+		{soql, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/query", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: url.Values{"q": {test.query}}}, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		want := &restapi.QueryResult{Done: true, TotalSize: 1,
+			Records: []restapi.SObject{{"Id": "01p", "Name": "Hello"}}}
+		requestFunc := func() (interface{}, error) {
+			return client.Query(&QueryInput{Query: test.query})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*QueryOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, want, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: want}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestQueryMore(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	nextURL := fmt.Sprintf("/services/data/%s/tooling/query/01g-2000", apiVersion)
+	tests := []struct {
+		nextRecordsURL string
+		statusCode     int
+		requestCount   int
+		errSnippet     string
+	}{
+		{"", 0, 0, "missing next records url"},
+		{nextURL, 200, 1, ""},
+		{nextURL, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, emptyBodyValidator,
+			&testserver.PathValidator{Path: test.nextRecordsURL}, getMethodValidator}
+
+		want := &restapi.QueryResult{Done: true, TotalSize: 2001,
+			Records: []restapi.SObject{{"Id": "01p"}}}
+		requestFunc := func() (interface{}, error) {
+			return client.QueryMore(&QueryMoreInput{NextRecordsURL: test.nextRecordsURL})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*QueryMoreOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, want, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: want}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestQueryAll(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	page := func(done bool, nextURL string, ids ...string) testserver.ResponseHandler {
+		records := []restapi.SObject{}
+		for _, id := range ids {
+			records = append(records, restapi.SObject{"Id": id})
+		}
+		return &testserver.JSONResponseHandler{
+			StatusCode: http.StatusOK,
+			Body: &restapi.QueryResult{Done: done, TotalSize: 3, NextRecordsURL: nextURL,
+				Records: records},
+		}
+	}
+	errorPage := &testserver.JSONResponseHandler{
+		StatusCode: http.StatusBadRequest,
+		Body:       []interface{}{genericErr},
+	}
+
+	tests := []struct {
+		pages        []testserver.ResponseHandler
+		maxRecords   int
+		requestCount int
+		errSnippet   string
+		want         []string
+	}{
+		{[]testserver.ResponseHandler{page(true, "", "a", "b", "c")}, 0, 1, "", []string{"a", "b", "c"}},
+		{[]testserver.ResponseHandler{page(false, "next/1", "a"), page(false, "next/2", "b"),
+			page(true, "", "c")}, 3, 3, "", []string{"a", "b", "c"}},
+		{[]testserver.ResponseHandler{page(false, "next/1", "a"), page(false, "next/2", "b"),
+			page(true, "", "c")}, 2, 1, "query returned more than 2 records", nil},
+		{[]testserver.ResponseHandler{page(false, "next/1", "a"), errorPage}, 0, 2, "GENERIC_ERROR", nil},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: test.pages},
+			authTokenValidator, getMethodValidator)
+		server.RequestCount = 0
+
+		records, err := client.QueryAll(context.Background(), "SELECT Id FROM ApexClass",
+			test.maxRecords)
+		assert.Equal(t, test.requestCount, server.RequestCount, assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		assert.Nil(t, err, assertMsg)
+		var got []string
+		for _, rec := range records {
+			got = append(got, rec["Id"].(string))
+		}
+		assert.Equal(t, test.want, got, assertMsg)
+	}
+}
+
+func assertRequest(t *testing.T, assertMsg string, server *testserver.Server, wantErr string,
+	invokeFunc func() (interface{}, error), successFunc func(interface{}),
+	expectedRequestCount int, validators []testserver.RequestValidator,
+	respHandler *testserver.JSONResponseHandler) {
+	shouldErr := wantErr != ""
+	// set server response
+	if shouldErr {
+		respHandler.Body = genericErr
+	}
+	server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg, respHandler, validators...)
+
+	// invoke request
+	server.RequestCount = 0 // reset counter
+	out, err := invokeFunc()
+
+	// assertions
+	assert.Equal(t, expectedRequestCount, server.RequestCount, assertMsg)
+	if shouldErr {
+		if assert.Error(t, err, assertMsg) {
+			assert.Contains(t, err.Error(), wantErr, assertMsg)
+		}
+	} else {
+		assert.Nil(t, err, assertMsg)
+		if successFunc != nil {
+			successFunc(out)
+		}
+	}
+}
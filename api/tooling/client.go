@@ -0,0 +1,32 @@
+package toolingapi
+
+import (
+	"net/http"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+	"github.com/Laugusti/go-sforce/sforce/session"
+)
+
+// Client handles request/response with the Salesforce Tooling API.
+type Client struct {
+	sess *session.Session
+}
+
+// NewClient returns a new tooling client for the Salesforce session.
+func NewClient(sess *session.Session) *Client {
+	return &Client{sess}
+}
+
+func (c *Client) newRequest(op *request.Operation, resultType request.ResultType,
+	result interface{}, statusCodes ...int) *request.Request {
+	return request.New(c.sess, op,
+		request.NewResultExpectation(resultType, statusCodes...),
+		result, c.setAuthAndContentTypeFunc())
+}
+
+func (c *Client) setAuthAndContentTypeFunc() func(*http.Request) {
+	return func(r *http.Request) {
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Authorization", "Bearer "+c.sess.AccessToken())
+	}
+}
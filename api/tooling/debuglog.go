@@ -0,0 +1,232 @@
+package toolingapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+const (
+	// maxTraceFlagDuration is the maximum time between the start and expiration of a trace
+	// flag.
+	maxTraceFlagDuration = 24 * time.Hour
+	// defaultLogType is the log type of a trace flag for a user.
+	defaultLogType = "USER_DEBUG"
+)
+
+// SetTraceFlagInput stores the input for creating or refreshing the trace flag of a user (or
+// Apex class or trigger). The debug level is created or updated using its developer name.
+// The log type defaults to USER_DEBUG, and the trace flag expires after the duration, which
+// cannot exceed 24 hours.
+type SetTraceFlagInput struct {
+	TracedEntityID string
+	DebugLevel     *DebugLevel
+	LogType        string
+	Duration       time.Duration
+}
+
+// SetTraceFlagOutput stores the output after setting a trace flag. Created is true if the
+// trace flag did not exist. Otherwise, previous has the DebugLevelId, StartDate and
+// ExpirationDate of the existing trace flag before it was refreshed, so that it can be
+// restored with UpdateSObject.
+type SetTraceFlagOutput struct {
+	TraceFlagID    string
+	DebugLevelID   string
+	ExpirationDate time.Time
+	Created        bool
+	Previous       restapi.SObject
+}
+
+// SetTraceFlag creates the trace flag, or refreshes the existing trace flag of the traced
+// entity and log type, using the Salesforce Tooling API. Logs are generated for the traced
+// entity until the trace flag expires.
+func (c *Client) SetTraceFlag(ctx context.Context, input *SetTraceFlagInput) (*SetTraceFlagOutput, error) {
+	// validate parameters
+	if !idRE.MatchString(input.TracedEntityID) {
+		return nil, errors.New("invalid traced entity id")
+	}
+	if input.DebugLevel == nil || !restapi.IsValidFieldName(input.DebugLevel.DeveloperName) {
+		return nil, errors.New("invalid debug level developer name")
+	}
+	if input.Duration <= 0 || input.Duration > maxTraceFlagDuration {
+		return nil, fmt.Errorf("duration must be between 0 and %v", maxTraceFlagDuration)
+	}
+	logType := input.LogType
+	if logType == "" {
+		logType = defaultLogType
+	}
+
+	debugLevelID, err := c.saveDebugLevel(ctx, input.DebugLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	// truncate to seconds, the api ignores fractional seconds
+	start := time.Now().UTC().Truncate(time.Second)
+	out := &SetTraceFlagOutput{DebugLevelID: debugLevelID,
+		ExpirationDate: start.Add(input.Duration)}
+	traceFlag := restapi.SObject{
+		"DebugLevelId":   debugLevelID,
+		"StartDate":      start.Format(restapi.DateTimeParamLayout),
+		"ExpirationDate": out.ExpirationDate.Format(restapi.DateTimeParamLayout),
+	}
+
+	// refresh existing trace flag
+	var existing []restapi.SObject
+	soql := "SELECT Id, DebugLevelId, StartDate, ExpirationDate FROM TraceFlag " +
+		"WHERE TracedEntityId = '" + input.TracedEntityID + "' AND LogType = " + quote(logType)
+	if err := c.queryInto(ctx, soql, &existing); err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		out.TraceFlagID, _ = existing[0]["Id"].(string)
+		out.Previous = restapi.SObject{
+			"DebugLevelId":   existing[0]["DebugLevelId"],
+			"StartDate":      existing[0]["StartDate"],
+			"ExpirationDate": existing[0]["ExpirationDate"],
+		}
+		_, err := c.UpdateSObject(&UpdateSObjectInput{
+			SObjectName: "TraceFlag",
+			SObjectID:   out.TraceFlagID,
+			SObject:     traceFlag,
+		})
+		return out, err
+	}
+
+	// create trace flag
+	traceFlag["TracedEntityId"] = input.TracedEntityID
+	traceFlag["LogType"] = logType
+	created, err := c.CreateSObject(&CreateSObjectInput{
+		SObjectName: "TraceFlag",
+		SObject:     traceFlag,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out.TraceFlagID, out.Created = created.Result.ID, true
+	return out, nil
+}
+
+// saveDebugLevel creates or updates the debug level with the developer name and returns its
+// id.
+func (c *Client) saveDebugLevel(ctx context.Context, level *DebugLevel) (string, error) {
+	sobj, err := level.sObject()
+	if err != nil {
+		return "", err
+	}
+
+	var existing []*idRecord
+	soql := "SELECT Id FROM DebugLevel WHERE DeveloperName = " + quote(level.DeveloperName)
+	if err := c.queryInto(ctx, soql, &existing); err != nil {
+		return "", err
+	}
+	if len(existing) > 0 {
+		_, err := c.UpdateSObject(&UpdateSObjectInput{
+			SObjectName: "DebugLevel",
+			SObjectID:   existing[0].ID,
+			SObject:     sobj,
+		})
+		return existing[0].ID, err
+	}
+
+	created, err := c.CreateSObject(&CreateSObjectInput{
+		SObjectName: "DebugLevel",
+		SObject:     sobj,
+	})
+	if err != nil {
+		return "", err
+	}
+	return created.Result.ID, nil
+}
+
+// ListApexLogsInput stores the input for listing debug logs. The logs are filtered by user
+// and start time if set. A limit of zero or less means there is no limit.
+type ListApexLogsInput struct {
+	UserID     string
+	Since      time.Time
+	Limit      int
+	Descending bool
+}
+
+// ListApexLogsOutput stores the output after listing debug logs.
+type ListApexLogsOutput struct {
+	Logs []*ApexLog
+}
+
+// ListApexLogs lists the debug logs, starting with the oldest, using the Salesforce Tooling
+// API, or starting with the newest if descending is true. The logs started at the since time
+// are included.
+func (c *Client) ListApexLogs(ctx context.Context, input *ListApexLogsInput) (*ListApexLogsOutput, error) {
+	soql := "SELECT Id, LogUserId, LogLength, Operation, Request, Status, " +
+		"DurationMilliseconds, StartTime, Location FROM ApexLog"
+	where := " WHERE "
+	if input.UserID != "" {
+		// validate parameters
+		if !idRE.MatchString(input.UserID) {
+			return nil, errors.New("invalid user id")
+		}
+		soql += where + "LogUserId = '" + input.UserID + "'"
+		where = " AND "
+	}
+	if !input.Since.IsZero() {
+		soql += where + "StartTime >= " + input.Since.UTC().Format(restapi.DateTimeParamLayout)
+	}
+	if input.Descending {
+		soql += " ORDER BY StartTime DESC, Id DESC"
+	} else {
+		soql += " ORDER BY StartTime, Id"
+	}
+	if input.Limit > 0 {
+		soql += fmt.Sprintf(" LIMIT %d", input.Limit)
+	}
+
+	var logs []*ApexLog
+	if err := c.queryInto(ctx, soql, &logs); err != nil {
+		return nil, err
+	}
+	return &ListApexLogsOutput{logs}, nil
+}
+
+// GetApexLogBodyInput stores the input for downloading a debug log.
+type GetApexLogBodyInput struct {
+	LogID string
+}
+
+// GetApexLogBodyOutput stores the output after downloading a debug log. The caller must close
+// the body.
+type GetApexLogBodyOutput struct {
+	Body io.ReadCloser
+}
+
+// GetApexLogBody downloads the body of the debug log from the Salesforce Tooling API. The
+// body is streamed instead of read into memory.
+func (c *Client) GetApexLogBody(input *GetApexLogBodyInput) (*GetApexLogBodyOutput, error) {
+	// validate parameters
+	if input.LogID == "" {
+		return nil, errors.New("log id is required")
+	}
+	if !idRE.MatchString(input.LogID) {
+		return nil, errors.New("invalid log id")
+	}
+
+	var body io.ReadCloser
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodGet,
+		APIPath: c.sObjectPath("ApexLog", input.LogID, "Body"),
+	}, request.StreamResult, &body, http.StatusOK)
+	if err := req.Send(); err != nil {
+		return nil, err
+	}
+	return &GetApexLogBodyOutput{body}, nil
+}
+
+// idRecord is a tooling record with only the id.
+type idRecord struct {
+	ID string `json:"Id"`
+}
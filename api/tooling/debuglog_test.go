@@ -0,0 +1,250 @@
+package toolingapi
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+// requestRecorder records the method and path of each request.
+type requestRecorder struct {
+	requests []string
+}
+
+// Validate implements the RequestValidator interface.
+func (r *requestRecorder) Validate(req *http.Request) error {
+	r.requests = append(r.requests, req.Method+" "+req.URL.Path)
+	return nil
+}
+
+func TestSetTraceFlag(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	userID := "005x00000012Q9P"
+	level := &DebugLevel{DeveloperName: "SforceCLI", ApexCode: "FINEST"}
+	created := func(id string) testserver.ResponseHandler {
+		return &testserver.JSONResponseHandler{StatusCode: http.StatusCreated,
+			Body: &restapi.UpsertResult{ID: id, Success: true}}
+	}
+	noContent := &testserver.JSONResponseHandler{StatusCode: http.StatusNoContent}
+	errorPage := &testserver.JSONResponseHandler{
+		StatusCode: http.StatusBadRequest,
+		Body:       []interface{}{genericErr},
+	}
+	queryPath := fmt.Sprintf("GET /services/data/%s/tooling/query", apiVersion)
+	sObjectPath := fmt.Sprintf("/services/data/%s/tooling/sobjects/", apiVersion)
+
+	tests := []struct {
+		tracedEntityID string
+		level          *DebugLevel
+		duration       time.Duration
+		pages          []testserver.ResponseHandler
+		wantRequests   []string
+		errSnippet     string
+		want           *SetTraceFlagOutput
+	}{
+		{"", level, time.Hour, nil, nil, "invalid traced entity id", nil},
+		{userID, nil, time.Hour, nil, nil, "invalid debug level developer name", nil},
+		{userID, &DebugLevel{DeveloperName: "1"}, time.Hour, nil, nil, "invalid debug level developer name", nil},
+		{userID, level, 0, nil, nil, "duration must be between", nil},
+		{userID, level, 25 * time.Hour, nil, nil, "duration must be between", nil},
+		{userID, level, time.Hour,
+			[]testserver.ResponseHandler{queryPage(), created("7dl1"), queryPage(), created("7tf1")},
+			[]string{queryPath, "POST " + sObjectPath + "DebugLevel", queryPath,
+				"POST " + sObjectPath + "TraceFlag"},
+			"", &SetTraceFlagOutput{TraceFlagID: "7tf1", DebugLevelID: "7dl1", Created: true}},
+		{userID, level, time.Hour,
+			[]testserver.ResponseHandler{queryPage(restapi.SObject{"Id": "7dl2"}), noContent,
+				queryPage(restapi.SObject{"Id": "7tf2", "DebugLevelId": "7dl0", "StartDate": nil,
+					"ExpirationDate": "2020-01-02T08:04:06.000+0000"}), noContent},
+			[]string{queryPath, "PATCH " + sObjectPath + "DebugLevel/7dl2", queryPath,
+				"PATCH " + sObjectPath + "TraceFlag/7tf2"},
+			"", &SetTraceFlagOutput{TraceFlagID: "7tf2", DebugLevelID: "7dl2",
+				Previous: restapi.SObject{"DebugLevelId": "7dl0", "StartDate": nil,
+					"ExpirationDate": "2020-01-02T08:04:06.000+0000"}}},
+		{userID, level, time.Hour,
+			[]testserver.ResponseHandler{queryPage(), errorPage},
+			[]string{queryPath, "POST " + sObjectPath + "DebugLevel"}, "GENERIC_ERROR", nil},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		pages := test.pages
+		if pages == nil {
+			pages = []testserver.ResponseHandler{errorPage}
+		}
+		recorder := &requestRecorder{}
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: pages},
+			authTokenValidator, jsonContentTypeValidator, recorder)
+		server.RequestCount = 0
+
+		out, err := client.SetTraceFlag(context.Background(), &SetTraceFlagInput{
+			TracedEntityID: test.tracedEntityID,
+			DebugLevel:     test.level,
+			Duration:       test.duration,
+		})
+		assert.Equal(t, test.wantRequests, recorder.requests, assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		if assert.Nil(t, err, assertMsg) {
+			assert.WithinDuration(t, time.Now().Add(test.duration), out.ExpirationDate,
+				2*time.Second, assertMsg)
+			out.ExpirationDate = time.Time{}
+			assert.Equal(t, test.want, out, assertMsg)
+		}
+	}
+}
+
+func TestDebugLevelSObject(t *testing.T) {
+	sobj, err := (&DebugLevel{DeveloperName: "SforceCLI", ApexCode: "FINEST"}).sObject()
+	assert.Nil(t, err)
+	assert.Equal(t, restapi.SObject{"DeveloperName": "SforceCLI", "MasterLabel": "SforceCLI",
+		"ApexCode": "FINEST"}, sobj)
+
+	sobj, err = (&DebugLevel{DeveloperName: "SforceCLI", MasterLabel: "CLI"}).sObject()
+	assert.Nil(t, err)
+	assert.Equal(t, restapi.SObject{"DeveloperName": "SforceCLI", "MasterLabel": "CLI"}, sobj)
+}
+
+func TestListApexLogs(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	since := time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("EST", -5*60*60))
+	log := restapi.SObject{"Id": "07L1", "LogUserId": "005x00000012Q9P", "LogLength": 1024,
+		"Operation": "/apex/Page", "Request": "Application", "Status": "Success",
+		"DurationMilliseconds": 42, "StartTime": "2020-01-02T08:04:06.000+0000",
+		"Location": "Monitoring"}
+	wantLog := &ApexLog{ID: "07L1", LogUserID: "005x00000012Q9P", LogLength: 1024,
+		Operation: "/apex/Page", Request: "Application", Status: "Success",
+		DurationMilliseconds: 42, StartTime: time.Date(2020, 1, 2, 8, 4, 6, 0, time.UTC),
+		Location: "Monitoring"}
+	tests := []struct {
+		userID       string
+		since        time.Time
+		limit        int
+		descending   bool
+		where        string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"005'", time.Time{}, 0, false, "", 0, 0, "invalid user id"},
+		{"", time.Time{}, 0, false, "", 200, 1, ""},
+		{"005x00000012Q9P", time.Time{}, 10, false, " WHERE LogUserId = '005x00000012Q9P'", 200, 1, ""},
+		{"005x00000012Q9P", time.Time{}, 1, true, " WHERE LogUserId = '005x00000012Q9P'", 200, 1, ""},
+		{"", since, 0, false, " WHERE StartTime >= 2020-01-02T08:04:05Z", 200, 1, ""},
+		{"005x00000012Q9P", since, 0, false,
+			" WHERE LogUserId = '005x00000012Q9P' AND StartTime >= 2020-01-02T08:04:05Z", 200, 1, ""},
+		{"", time.Time{}, 0, false, "", 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/query", apiVersion)
+		soql := "SELECT Id, LogUserId, LogLength, Operation, Request, Status, " +
+			"DurationMilliseconds, StartTime, Location FROM ApexLog" + test.where +
+			" ORDER BY StartTime, Id"
+		if test.descending {
+			soql = strings.Replace(soql, "StartTime, Id", "StartTime DESC, Id DESC", 1)
+		}
+		if test.limit > 0 {
+			soql += fmt.Sprintf(" LIMIT %d", test.limit)
+		}
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: url.Values{"q": {soql}}}, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.ListApexLogs(context.Background(), &ListApexLogsInput{
+				UserID:     test.userID,
+				Since:      test.since,
+				Limit:      test.limit,
+				Descending: test.descending,
+			})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*ListApexLogsOutput)
+			if assert.True(t, ok, assertMsg) && assert.Len(t, out.Logs, 1, assertMsg) {
+				assert.True(t, wantLog.StartTime.Equal(out.Logs[0].StartTime), assertMsg)
+				out.Logs[0].StartTime = wantLog.StartTime
+				assert.Equal(t, wantLog, out.Logs[0], assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode,
+			Body: &restapi.QueryResult{Done: true, TotalSize: 1, Records: []restapi.SObject{log}}}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestApexLogUnmarshalJSON(t *testing.T) {
+	var log ApexLog
+	assert.Nil(t, log.UnmarshalJSON([]byte(`{"Id":"07L1","StartTime":null}`)))
+	assert.Equal(t, ApexLog{ID: "07L1"}, log)
+
+	err := log.UnmarshalJSON([]byte(`{"Id":"07L1","StartTime":"yesterday"}`))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid start time")
+	}
+}
+
+func TestGetApexLogBody(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		logID        string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", 0, 0, "log id is required"},
+		{"07L/../Account", 0, 0, "invalid log id"},
+		{"07L000000000001", 200, 1, ""},
+		{"07L000000000001", 404, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/sobjects/ApexLog/%s/Body", apiVersion,
+			test.logID)
+		validators := []testserver.RequestValidator{authTokenValidator, emptyQueryValidator,
+			emptyBodyValidator, &testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.GetApexLogBody(&GetApexLogBodyInput{LogID: test.logID})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*GetApexLogBodyOutput)
+			if !assert.True(t, ok, assertMsg) {
+				return
+			}
+			b, err := ioutil.ReadAll(out.Body)
+			assert.Nil(t, err, assertMsg)
+			assert.Nil(t, out.Body.Close(), assertMsg)
+			assert.Contains(t, string(b), "USER_DEBUG", assertMsg)
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode,
+			Body: "48.0 APEX_CODE,FINEST\n12:00:00.0 (1)|USER_DEBUG|[1]|DEBUG|hello"}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
@@ -0,0 +1,55 @@
+package toolingapi
+
+import (
+	"errors"
+	"net/http"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+// DescribeGlobalInput stores the input for describing the available tooling SObjects.
+type DescribeGlobalInput struct {
+}
+
+// DescribeGlobalOutput stores the output after describing the available tooling SObjects.
+type DescribeGlobalOutput struct {
+	Result *restapi.DescribeGlobalResult
+}
+
+// DescribeGlobal lists the tooling SObjects available in the organization using the
+// Salesforce Tooling API.
+func (c *Client) DescribeGlobal(input *DescribeGlobalInput) (*DescribeGlobalOutput, error) {
+	var result restapi.DescribeGlobalResult
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodGet,
+		APIPath: c.sObjectPath(),
+	}, request.JSONResult, &result, http.StatusOK)
+	return &DescribeGlobalOutput{&result}, req.Send()
+}
+
+// DescribeSObjectInput stores the input for describing a tooling SObject.
+type DescribeSObjectInput struct {
+	SObjectName string
+}
+
+// DescribeSObjectOutput stores the output after describing a tooling SObject.
+type DescribeSObjectOutput struct {
+	Result *restapi.DescribeSObjectResult
+}
+
+// DescribeSObject retrieves the metadata (fields, relationships, etc.) of the tooling
+// SObject using the Salesforce Tooling API.
+func (c *Client) DescribeSObject(input *DescribeSObjectInput) (*DescribeSObjectOutput, error) {
+	// validate parameters
+	if !restapi.IsValidFieldName(input.SObjectName) {
+		return nil, errors.New("invalid sobject name")
+	}
+
+	var result restapi.DescribeSObjectResult
+	req := c.newRequest(&request.Operation{
+		Method:  http.MethodGet,
+		APIPath: c.sObjectPath(input.SObjectName, "describe"),
+	}, request.JSONResult, &result, http.StatusOK)
+	return &DescribeSObjectOutput{&result}, req.Send()
+}
@@ -0,0 +1,89 @@
+package toolingapi
+
+import (
+	"fmt"
+	"testing"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeGlobal(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{200, 1, ""},
+		{400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/sobjects", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		want := &restapi.DescribeGlobalResult{Encoding: "UTF-8", MaxBatchSize: 200,
+			SObjects: []*restapi.DescribeGlobalSObject{{Name: "ApexClass"}, {Name: "TraceFlag"}}}
+		requestFunc := func() (interface{}, error) {
+			return client.DescribeGlobal(&DescribeGlobalInput{})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*DescribeGlobalOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, want, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: want}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestDescribeSObject(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	tests := []struct {
+		objectType   string
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", 0, 0, "invalid sobject name"},
+		{"ApexClass", 200, 1, ""},
+		{"ApexClass", 404, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/sobjects/%s/describe", apiVersion,
+			test.objectType)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			emptyQueryValidator, emptyBodyValidator,
+			&testserver.PathValidator{Path: path}, getMethodValidator}
+
+		want := &restapi.DescribeSObjectResult{Name: "ApexClass",
+			Fields: []*restapi.DescribeField{{Name: "Body", Type: "textarea"}}}
+		requestFunc := func() (interface{}, error) {
+			return client.DescribeSObject(&DescribeSObjectInput{SObjectName: test.objectType})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*DescribeSObjectOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, want, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: want}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
@@ -0,0 +1,52 @@
+package toolingapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Laugusti/go-sforce/sforce/request"
+)
+
+const (
+	executeAnonymousPath = "/services/data/%s/tooling/executeAnonymous/"
+	// maxEncodedApexBodyLength is the maximum length of the URL encoded anonymous Apex. The
+	// Apex is sent in the query string and Salesforce rejects request URIs longer than 16,384
+	// bytes, so some room is left for the path.
+	maxEncodedApexBodyLength = 16000
+)
+
+// ExecuteAnonymousInput stores the input for executing anonymous Apex.
+type ExecuteAnonymousInput struct {
+	ApexBody string
+}
+
+// ExecuteAnonymousOutput stores the output after executing anonymous Apex. The Apex failed to
+// compile or threw an exception if the result is not successful.
+type ExecuteAnonymousOutput struct {
+	Result *ExecuteAnonymousResult
+}
+
+// ExecuteAnonymous compiles and executes the anonymous Apex using the Salesforce Tooling API.
+// A compile or runtime failure is not returned as an error, see ExecuteAnonymousResult.Err.
+// The URL encoded Apex cannot be longer than 16,000 bytes.
+func (c *Client) ExecuteAnonymous(input *ExecuteAnonymousInput) (*ExecuteAnonymousOutput, error) {
+	// validate parameters
+	if input.ApexBody == "" {
+		return nil, errors.New("apex body is required")
+	}
+	query := "anonymousBody=" + url.QueryEscape(input.ApexBody)
+	if n := len(query) - len("anonymousBody="); n > maxEncodedApexBodyLength {
+		return nil, fmt.Errorf("url encoded apex body is %d bytes, the limit is %d bytes", n,
+			maxEncodedApexBodyLength)
+	}
+
+	var result ExecuteAnonymousResult
+	req := c.newRequest(&request.Operation{
+		Method:   http.MethodGet,
+		APIPath:  fmt.Sprintf(executeAnonymousPath, c.sess.APIVersion),
+		RawQuery: query,
+	}, request.JSONResult, &result, http.StatusOK)
+	return &ExecuteAnonymousOutput{&result}, req.Send()
+}
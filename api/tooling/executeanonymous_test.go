@@ -0,0 +1,83 @@
+package toolingapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteAnonymous(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	apex := "System.debug('Hello, World!');"
+	success := &ExecuteAnonymousResult{Line: -1, Column: -1, Compiled: true, Success: true}
+	compileErr := &ExecuteAnonymousResult{Line: 1, Column: 14, CompileProblem: "Unexpected token '('."}
+	tests := []struct {
+		apexBody     string
+		result       *ExecuteAnonymousResult
+		statusCode   int
+		requestCount int
+		errSnippet   string
+	}{
+		{"", nil, 0, 0, "apex body is required"},
+		{strings.Repeat("a", maxEncodedApexBodyLength), success, 200, 1, ""},
+		{strings.Repeat("a;", maxEncodedApexBodyLength/4+1), nil, 0, 0,
+			"url encoded apex body is 16004 bytes, the limit is 16000 bytes"},
+		{apex, success, 200, 1, ""},
+		{apex, compileErr, 200, 1, ""},
+		{apex, success, 400, 1, "GENERIC_ERROR"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		path := fmt.Sprintf("/services/data/%s/tooling/executeAnonymous", apiVersion)
+		validators := []testserver.RequestValidator{authTokenValidator, jsonContentTypeValidator,
+			&testserver.QueryValidator{Query: url.Values{"anonymousBody": {test.apexBody}}},
+			emptyBodyValidator, &testserver.PathValidator{Path: path}, getMethodValidator}
+
+		requestFunc := func() (interface{}, error) {
+			return client.ExecuteAnonymous(&ExecuteAnonymousInput{ApexBody: test.apexBody})
+		}
+		successFunc := func(res interface{}) {
+			out, ok := res.(*ExecuteAnonymousOutput)
+			if assert.True(t, ok, assertMsg) {
+				assert.Equal(t, test.result, out.Result, assertMsg)
+			}
+		}
+		handler := &testserver.JSONResponseHandler{StatusCode: test.statusCode, Body: test.result}
+
+		assertRequest(t, assertMsg, server, test.errSnippet, requestFunc, successFunc,
+			test.requestCount, validators, handler)
+	}
+}
+
+func TestExecuteAnonymousResultErr(t *testing.T) {
+	tests := []struct {
+		result  *ExecuteAnonymousResult
+		wantErr string
+	}{
+		{&ExecuteAnonymousResult{Line: -1, Column: -1, Compiled: true, Success: true}, ""},
+		{&ExecuteAnonymousResult{Line: 1, Column: 14, CompileProblem: "Unexpected token '('."},
+			"compile error at line 1 column 14: Unexpected token '('."},
+		{&ExecuteAnonymousResult{Line: 2, Column: 1, Compiled: true,
+			ExceptionMessage:    "System.NullPointerException: Attempt to de-reference a null object",
+			ExceptionStackTrace: "AnonymousBlock: line 2, column 1"},
+			"runtime error at line 2 column 1: System.NullPointerException: Attempt to de-reference a null object\nAnonymousBlock: line 2, column 1"},
+		{&ExecuteAnonymousResult{Line: 3, Column: 1, Compiled: true, ExceptionMessage: "System.LimitException"},
+			"runtime error at line 3 column 1: System.LimitException"},
+	}
+
+	for _, test := range tests {
+		err := test.result.Err()
+		if test.wantErr == "" {
+			assert.Nil(t, err, test.result)
+		} else if assert.Error(t, err, test.result) {
+			assert.Equal(t, test.wantErr, err.Error(), test.result)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package toolingapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+)
+
+// member types of a metadata container
+const (
+	MemberTypeApexClass   = "ApexClass"
+	MemberTypeApexTrigger = "ApexTrigger"
+)
+
+// containerNamePrefix is the prefix of the metadata container names. Container names are at
+// most 32 characters.
+const containerNamePrefix = "GoSforce"
+
+// ApexMember is the body of an existing Apex class or trigger to save.
+type ApexMember struct {
+	Type string
+	Name string
+	Body string
+}
+
+// SaveApexInput stores the input for saving Apex classes and triggers. If CheckOnly is true,
+// the Apex is compiled but not saved. The deployment is polled every poll interval, which
+// defaults to 5 seconds.
+type SaveApexInput struct {
+	Members      []*ApexMember
+	CheckOnly    bool
+	PollInterval time.Duration
+}
+
+// SaveApexOutput stores the output after saving Apex classes and triggers. The Apex failed to
+// compile or save if the request is not completed, see ContainerAsyncRequest.Err.
+type SaveApexOutput struct {
+	Result *ContainerAsyncRequest
+}
+
+// SaveApex compiles and saves the existing Apex classes and triggers using a metadata
+// container and the Salesforce Tooling API, without a Metadata API deploy. The members are
+// added to a new metadata container, which is deployed and polled until the deployment is
+// done. The container is deleted afterwards. New classes and triggers must be created with
+// CreateSObject.
+func (c *Client) SaveApex(ctx context.Context, input *SaveApexInput) (*SaveApexOutput, error) {
+	// validate parameters
+	if len(input.Members) == 0 {
+		return nil, errors.New("apex members are required")
+	}
+	namesByType := make(map[string][]string)
+	for _, m := range input.Members {
+		if m.Type != MemberTypeApexClass && m.Type != MemberTypeApexTrigger {
+			return nil, fmt.Errorf("invalid member type %q", m.Type)
+		}
+		if !restapi.IsValidFieldName(m.Name) {
+			return nil, fmt.Errorf("invalid member name %q", m.Name)
+		}
+		if m.Body == "" {
+			return nil, fmt.Errorf("body of %s %s is required", m.Type, m.Name)
+		}
+		namesByType[m.Type] = append(namesByType[m.Type], m.Name)
+	}
+	pollInterval := input.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+
+	// get ids of classes and triggers
+	ids := make(map[string]string)
+	for _, memberType := range []string{MemberTypeApexClass, MemberTypeApexTrigger} {
+		names := namesByType[memberType]
+		if len(names) == 0 {
+			continue
+		}
+		quoted := make([]string, len(names))
+		for i, name := range names {
+			quoted[i] = quote(name)
+		}
+		var records []*struct {
+			ID   string `json:"Id"`
+			Name string `json:"Name"`
+		}
+		soql := "SELECT Id, Name FROM " + memberType + " WHERE Name IN (" +
+			strings.Join(quoted, ", ") + ")"
+		if err := c.queryInto(ctx, soql, &records); err != nil {
+			return nil, err
+		}
+		for _, rec := range records {
+			ids[memberType+"."+strings.ToLower(rec.Name)] = rec.ID
+		}
+	}
+	for _, m := range input.Members {
+		if ids[m.Type+"."+strings.ToLower(m.Name)] == "" {
+			return nil, fmt.Errorf("%s %s does not exist", m.Type, m.Name)
+		}
+	}
+
+	// create container
+	container, err := c.CreateSObject(&CreateSObjectInput{
+		SObjectName: "MetadataContainer",
+		SObject: restapi.SObject{
+			"Name": fmt.Sprintf("%s%d", containerNamePrefix, time.Now().UnixNano()),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.deployContainer(ctx, container.Result.ID, input, ids, pollInterval)
+
+	// delete container
+	_, delErr := c.DeleteSObject(&DeleteSObjectInput{
+		SObjectName: "MetadataContainer",
+		SObjectID:   container.Result.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if delErr != nil {
+		return nil, fmt.Errorf("failed to delete metadata container: %v", delErr)
+	}
+	return &SaveApexOutput{result}, nil
+}
+
+// deployContainer adds the members to the metadata container, then deploys the container and
+// polls the deployment until it is done.
+func (c *Client) deployContainer(ctx context.Context, containerID string, input *SaveApexInput,
+	ids map[string]string, pollInterval time.Duration) (*ContainerAsyncRequest, error) {
+	// add members
+	for _, m := range input.Members {
+		_, err := c.CreateSObject(&CreateSObjectInput{
+			SObjectName: m.Type + "Member",
+			SObject: restapi.SObject{
+				"MetadataContainerId": containerID,
+				"ContentEntityId":     ids[m.Type+"."+strings.ToLower(m.Name)],
+				"Body":                m.Body,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// deploy container
+	deploy, err := c.CreateSObject(&CreateSObjectInput{
+		SObjectName: "ContainerAsyncRequest",
+		SObject: restapi.SObject{
+			"MetadataContainerId": containerID,
+			"IsCheckOnly":         input.CheckOnly,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// poll deployment
+	for {
+		out, err := c.GetSObject(&GetSObjectInput{
+			SObjectName: "ContainerAsyncRequest",
+			SObjectID:   deploy.Result.ID,
+			Fields:      []string{"Id", "State", "IsCheckOnly", "ErrorMsg", "DeployDetails"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		var result ContainerAsyncRequest
+		if err := remarshal(out.SObject, &result); err != nil {
+			return nil, err
+		}
+		if result.Done() {
+			return &result, nil
+		}
+
+		// wait for next poll
+		timer := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
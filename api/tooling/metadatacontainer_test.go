@@ -0,0 +1,149 @@
+package toolingapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/Laugusti/go-sforce/internal/testserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveApex(t *testing.T) {
+	client, server := createClientAndServer(t)
+	defer server.Stop()
+
+	class := &ApexMember{MemberTypeApexClass, "Hello", "public class Hello {}"}
+	trigger := &ApexMember{MemberTypeApexTrigger, "AccountTrigger", "trigger AccountTrigger on Account (before insert) {}"}
+	created := func(id string) testserver.ResponseHandler {
+		return &testserver.JSONResponseHandler{StatusCode: http.StatusCreated,
+			Body: &restapi.UpsertResult{ID: id, Success: true}}
+	}
+	deployment := func(state string, details *DeployDetails) testserver.ResponseHandler {
+		return &testserver.JSONResponseHandler{StatusCode: http.StatusOK,
+			Body: &ContainerAsyncRequest{ID: "1dr1", State: state, DeployDetails: details}}
+	}
+	noContent := &testserver.JSONResponseHandler{StatusCode: http.StatusNoContent}
+	errorPage := &testserver.JSONResponseHandler{
+		StatusCode: http.StatusBadRequest,
+		Body:       []interface{}{genericErr},
+	}
+	failure := &DeployMessage{ComponentType: "ApexClass", FullName: "Hello", LineNumber: 1,
+		ColumnNumber: 8, Problem: "Unexpected token 'clas'.", ProblemType: "Error"}
+	queryPath := fmt.Sprintf("GET /services/data/%s/tooling/query", apiVersion)
+	sObjectPath := fmt.Sprintf("/services/data/%s/tooling/sobjects/", apiVersion)
+	deployRequests := []string{"POST " + sObjectPath + "MetadataContainer",
+		"POST " + sObjectPath + "ApexClassMember", "POST " + sObjectPath + "ContainerAsyncRequest",
+		"GET " + sObjectPath + "ContainerAsyncRequest/1dr1"}
+
+	tests := []struct {
+		members      []*ApexMember
+		pages        []testserver.ResponseHandler
+		wantRequests []string
+		errSnippet   string
+		wantState    string
+		wantErr      string
+	}{
+		{nil, nil, nil, "apex members are required", "", ""},
+		{[]*ApexMember{{"ApexPage", "Hello", "<apex:page/>"}}, nil, nil, `invalid member type "ApexPage"`, "", ""},
+		{[]*ApexMember{{MemberTypeApexClass, "1", "body"}}, nil, nil, `invalid member name "1"`, "", ""},
+		{[]*ApexMember{{MemberTypeApexClass, "Hello", ""}}, nil, nil, "body of ApexClass Hello is required", "", ""},
+		{[]*ApexMember{class}, []testserver.ResponseHandler{queryPage()}, []string{queryPath},
+			"ApexClass Hello does not exist", "", ""},
+		{[]*ApexMember{class}, []testserver.ResponseHandler{
+			queryPage(restapi.SObject{"Id": "01p1", "Name": "hello"}), created("1dc1"),
+			created("4001"), created("1dr1"), deployment("Queued", nil),
+			deployment("Completed", &DeployDetails{}), noContent},
+			append(append([]string{queryPath}, deployRequests...), "GET "+sObjectPath+"ContainerAsyncRequest/1dr1",
+				"DELETE "+sObjectPath+"MetadataContainer/1dc1"),
+			"", "Completed", ""},
+		{[]*ApexMember{class}, []testserver.ResponseHandler{
+			queryPage(restapi.SObject{"Id": "01p1", "Name": "Hello"}), created("1dc1"),
+			created("4001"), created("1dr1"),
+			deployment("Failed", &DeployDetails{ComponentFailures: []*DeployMessage{failure}}), noContent},
+			append(append([]string{queryPath}, deployRequests...), "DELETE "+sObjectPath+"MetadataContainer/1dc1"),
+			"", "Failed", "deployment failed: ApexClass Hello line 1 column 8: Unexpected token 'clas'."},
+		{[]*ApexMember{class, trigger}, []testserver.ResponseHandler{
+			queryPage(restapi.SObject{"Id": "01p1", "Name": "Hello"}),
+			queryPage(restapi.SObject{"Id": "01q1", "Name": "AccountTrigger"}), created("1dc1"),
+			created("4001"), created("4011"), created("1dr1"), deployment("Completed", nil), noContent},
+			[]string{queryPath, queryPath, "POST " + sObjectPath + "MetadataContainer",
+				"POST " + sObjectPath + "ApexClassMember", "POST " + sObjectPath + "ApexTriggerMember",
+				"POST " + sObjectPath + "ContainerAsyncRequest",
+				"GET " + sObjectPath + "ContainerAsyncRequest/1dr1",
+				"DELETE " + sObjectPath + "MetadataContainer/1dc1"},
+			"", "Completed", ""},
+		{[]*ApexMember{class}, []testserver.ResponseHandler{
+			queryPage(restapi.SObject{"Id": "01p1", "Name": "Hello"}), created("1dc1"),
+			errorPage, noContent},
+			[]string{queryPath, "POST " + sObjectPath + "MetadataContainer",
+				"POST " + sObjectPath + "ApexClassMember", "DELETE " + sObjectPath + "MetadataContainer/1dc1"},
+			"GENERIC_ERROR", "", ""},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		pages := test.pages
+		if pages == nil {
+			pages = []testserver.ResponseHandler{errorPage}
+		}
+		recorder := &requestRecorder{}
+		server.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.ConsecutiveResponseHandler{Handlers: pages},
+			authTokenValidator, jsonContentTypeValidator, recorder)
+		server.RequestCount = 0
+
+		out, err := client.SaveApex(context.Background(), &SaveApexInput{
+			Members:      test.members,
+			PollInterval: time.Millisecond,
+		})
+		assert.Equal(t, test.wantRequests, recorder.requests, assertMsg)
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, assertMsg) {
+			continue
+		}
+		assert.Equal(t, test.wantState, out.Result.State, assertMsg)
+		if test.wantErr == "" {
+			assert.Nil(t, out.Result.Err(), assertMsg)
+		} else if assert.Error(t, out.Result.Err(), assertMsg) {
+			assert.Equal(t, test.wantErr, out.Result.Err().Error(), assertMsg)
+		}
+	}
+}
+
+func TestContainerAsyncRequestErr(t *testing.T) {
+	tests := []struct {
+		request *ContainerAsyncRequest
+		wantErr string
+	}{
+		{&ContainerAsyncRequest{State: "Completed"}, ""},
+		{&ContainerAsyncRequest{State: "Aborted"}, "deployment aborted"},
+		{&ContainerAsyncRequest{State: "Error", ErrorMsg: "Unable to deploy"},
+			"deployment error: Unable to deploy"},
+		{&ContainerAsyncRequest{State: "Failed", DeployDetails: &DeployDetails{
+			ComponentFailures: []*DeployMessage{
+				{ComponentType: "ApexClass", FullName: "A", LineNumber: 2, ColumnNumber: 3, Problem: "x"},
+				{ComponentType: "ApexTrigger", FullName: "B"},
+			}}},
+			"deployment failed: ApexClass A line 2 column 3: x; ApexTrigger B"},
+	}
+
+	for _, test := range tests {
+		err := test.request.Err()
+		if test.wantErr == "" {
+			assert.Nil(t, err, test.request)
+		} else if assert.Error(t, err, test.request) {
+			assert.Equal(t, test.wantErr, err.Error(), test.request)
+		}
+	}
+	assert.False(t, (&ContainerAsyncRequest{State: "Queued"}).Done())
+	assert.True(t, (&ContainerAsyncRequest{State: "Invalidated"}).Done())
+}
@@ -0,0 +1,267 @@
+package toolingapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+)
+
+// ExecuteAnonymousResult is a successful response from the Salesforce Tooling API after
+// executing anonymous Apex. Line and Column locate the compile problem or exception, and are
+// -1 if the Apex succeeded.
+type ExecuteAnonymousResult struct {
+	Line                int    `json:"line"`
+	Column              int    `json:"column"`
+	Compiled            bool   `json:"compiled"`
+	Success             bool   `json:"success"`
+	CompileProblem      string `json:"compileProblem"`
+	ExceptionMessage    string `json:"exceptionMessage"`
+	ExceptionStackTrace string `json:"exceptionStackTrace"`
+}
+
+// Err returns an error describing the compile problem or exception. Returns nil if the Apex
+// compiled and executed successfully.
+func (r *ExecuteAnonymousResult) Err() error {
+	switch {
+	case !r.Compiled:
+		return fmt.Errorf("compile error at line %d column %d: %s", r.Line, r.Column,
+			r.CompileProblem)
+	case !r.Success && r.ExceptionStackTrace != "":
+		return fmt.Errorf("runtime error at line %d column %d: %s\n%s", r.Line, r.Column,
+			r.ExceptionMessage, r.ExceptionStackTrace)
+	case !r.Success:
+		return fmt.Errorf("runtime error at line %d column %d: %s", r.Line, r.Column,
+			r.ExceptionMessage)
+	default:
+		return nil
+	}
+}
+
+// ApexClassRef is a reference to an Apex class or trigger in a tooling record.
+type ApexClassRef struct {
+	Name string `json:"Name"`
+}
+
+// ApexTestQueueItem is a test class of a test run. The status is Holding, Queued, Preparing,
+// Processing, Completed, Failed or Aborted.
+type ApexTestQueueItem struct {
+	ID             string        `json:"Id"`
+	ApexClassID    string        `json:"ApexClassId"`
+	ApexClass      *ApexClassRef `json:"ApexClass"`
+	Status         string        `json:"Status"`
+	ExtendedStatus string        `json:"ExtendedStatus"`
+}
+
+// Processed returns true if the test class completed, failed or was aborted.
+func (i *ApexTestQueueItem) Processed() bool {
+	switch i.Status {
+	case "Completed", "Failed", "Aborted":
+		return true
+	default:
+		return false
+	}
+}
+
+// ClassName returns the name of the test class.
+func (i *ApexTestQueueItem) ClassName() string {
+	if i.ApexClass == nil {
+		return ""
+	}
+	return i.ApexClass.Name
+}
+
+// Failed returns true if the test class failed (e.g. did not compile) or was aborted. The test
+// methods of such a class have no results, and the reason is in the extended status.
+func (i *ApexTestQueueItem) Failed() bool {
+	return i.Status == "Failed" || i.Status == "Aborted"
+}
+
+// ApexTestResult is the result of a test method. The outcome is Pass, Fail, CompileFail or
+// Skip, and the run time is in milliseconds.
+type ApexTestResult struct {
+	ID          string        `json:"Id"`
+	QueueItemID string        `json:"QueueItemId"`
+	ApexClassID string        `json:"ApexClassId"`
+	ApexClass   *ApexClassRef `json:"ApexClass"`
+	MethodName  string        `json:"MethodName"`
+	Outcome     string        `json:"Outcome"`
+	Message     string        `json:"Message"`
+	StackTrace  string        `json:"StackTrace"`
+	RunTime     int           `json:"RunTime"`
+}
+
+// ClassName returns the name of the test class.
+func (r *ApexTestResult) ClassName() string {
+	if r.ApexClass == nil {
+		return ""
+	}
+	return r.ApexClass.Name
+}
+
+// Failed returns true if the test method failed or did not compile.
+func (r *ApexTestResult) Failed() bool {
+	return r.Outcome == "Fail" || r.Outcome == "CompileFail"
+}
+
+// ApexCodeCoverage is the aggregate code coverage of an Apex class or trigger.
+type ApexCodeCoverage struct {
+	ApexClassOrTriggerID string        `json:"ApexClassOrTriggerId"`
+	ApexClassOrTrigger   *ApexClassRef `json:"ApexClassOrTrigger"`
+	NumLinesCovered      int           `json:"NumLinesCovered"`
+	NumLinesUncovered    int           `json:"NumLinesUncovered"`
+}
+
+// Name returns the name of the Apex class or trigger.
+func (c *ApexCodeCoverage) Name() string {
+	if c.ApexClassOrTrigger == nil {
+		return ""
+	}
+	return c.ApexClassOrTrigger.Name
+}
+
+// Percent returns the percentage of lines covered. Returns 0 if there are no lines.
+func (c *ApexCodeCoverage) Percent() float64 {
+	total := c.NumLinesCovered + c.NumLinesUncovered
+	if total == 0 {
+		return 0
+	}
+	return float64(c.NumLinesCovered) / float64(total) * 100
+}
+
+// DebugLevel is the log levels (e.g. NONE, ERROR, WARN, INFO, DEBUG, FINE, FINER, FINEST) of
+// the log categories of a trace flag. The master label defaults to the developer name.
+type DebugLevel struct {
+	DeveloperName string `json:"DeveloperName"`
+	MasterLabel   string `json:"MasterLabel,omitempty"`
+	ApexCode      string `json:"ApexCode,omitempty"`
+	ApexProfiling string `json:"ApexProfiling,omitempty"`
+	Callout       string `json:"Callout,omitempty"`
+	Database      string `json:"Database,omitempty"`
+	System        string `json:"System,omitempty"`
+	Validation    string `json:"Validation,omitempty"`
+	Visualforce   string `json:"Visualforce,omitempty"`
+	Workflow      string `json:"Workflow,omitempty"`
+}
+
+// sObject returns the debug level as a SObject.
+func (l *DebugLevel) sObject() (restapi.SObject, error) {
+	level := *l
+	if level.MasterLabel == "" {
+		level.MasterLabel = level.DeveloperName
+	}
+	b, err := json.Marshal(&level)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't marshal debug level: %v", err)
+	}
+	var sobj restapi.SObject
+	if err := json.Unmarshal(b, &sobj); err != nil {
+		return nil, fmt.Errorf("couldn't marshal debug level: %v", err)
+	}
+	return sobj, nil
+}
+
+// ApexLog is a debug log. The log length is in bytes.
+type ApexLog struct {
+	ID                   string    `json:"Id"`
+	LogUserID            string    `json:"LogUserId"`
+	LogLength            int       `json:"LogLength"`
+	Operation            string    `json:"Operation"`
+	Request              string    `json:"Request"`
+	Status               string    `json:"Status"`
+	DurationMilliseconds int       `json:"DurationMilliseconds"`
+	StartTime            time.Time `json:"StartTime"`
+	Location             string    `json:"Location"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The start time is parsed using the
+// date/time layout of the Salesforce API.
+func (l *ApexLog) UnmarshalJSON(data []byte) error {
+	type apexLog ApexLog
+	aux := struct {
+		*apexLog
+		StartTime string `json:"StartTime"`
+	}{apexLog: (*apexLog)(l)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	l.StartTime = time.Time{}
+	if aux.StartTime == "" {
+		return nil
+	}
+	t, err := time.Parse(restapi.DateTimeLayout, aux.StartTime)
+	if err != nil {
+		return fmt.Errorf("invalid start time %q: %v", aux.StartTime, err)
+	}
+	l.StartTime = t
+	return nil
+}
+
+// ContainerAsyncRequest is the deployment of a metadata container. The state is Queued,
+// Invalidated, Completed, Failed, Error or Aborted.
+type ContainerAsyncRequest struct {
+	ID            string         `json:"Id"`
+	State         string         `json:"State"`
+	IsCheckOnly   bool           `json:"IsCheckOnly"`
+	ErrorMsg      string         `json:"ErrorMsg"`
+	DeployDetails *DeployDetails `json:"DeployDetails"`
+}
+
+// Done returns true if the deployment is no longer queued.
+func (r *ContainerAsyncRequest) Done() bool {
+	return r.State != "" && r.State != "Queued"
+}
+
+// Err returns an error describing the compile errors or the failure of the deployment.
+// Returns nil if the deployment completed.
+func (r *ContainerAsyncRequest) Err() error {
+	if r.State == "Completed" {
+		return nil
+	}
+	var msgs []string
+	if r.DeployDetails != nil {
+		for _, f := range r.DeployDetails.ComponentFailures {
+			msgs = append(msgs, f.String())
+		}
+	}
+	if r.ErrorMsg != "" {
+		msgs = append(msgs, r.ErrorMsg)
+	}
+	if len(msgs) == 0 {
+		return fmt.Errorf("deployment %s", strings.ToLower(r.State))
+	}
+	return fmt.Errorf("deployment %s: %s", strings.ToLower(r.State), strings.Join(msgs, "; "))
+}
+
+// DeployDetails is the result of each component of a deployment.
+type DeployDetails struct {
+	ComponentFailures  []*DeployMessage `json:"componentFailures"`
+	ComponentSuccesses []*DeployMessage `json:"componentSuccesses"`
+}
+
+// DeployMessage is the result of a component of a deployment. The line and column locate
+// the compile problem of a failed component.
+type DeployMessage struct {
+	ComponentType string `json:"componentType"`
+	FullName      string `json:"fullName"`
+	FileName      string `json:"fileName"`
+	LineNumber    int    `json:"lineNumber"`
+	ColumnNumber  int    `json:"columnNumber"`
+	Problem       string `json:"problem"`
+	ProblemType   string `json:"problemType"`
+	Success       bool   `json:"success"`
+	Changed       bool   `json:"changed"`
+	Created       bool   `json:"created"`
+	Deleted       bool   `json:"deleted"`
+}
+
+// String returns the component and its compile problem.
+func (m *DeployMessage) String() string {
+	if m.Problem == "" {
+		return fmt.Sprintf("%s %s", m.ComponentType, m.FullName)
+	}
+	return fmt.Sprintf("%s %s line %d column %d: %s", m.ComponentType, m.FullName,
+		m.LineNumber, m.ColumnNumber, m.Problem)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	toolingapi "github.com/Laugusti/go-sforce/api/tooling"
+	"github.com/Laugusti/go-sforce/sforce/session"
+	"github.com/spf13/cobra"
+)
+
+var (
+	toolingSession *session.Session
+	toolingClient  *toolingapi.Client
+)
+
+// apexCmd represents the apex command
+var apexCmd = &cobra.Command{
+	Use:   "apex",
+	Short: "The apex command uses the Salesforce Tooling API to run and manage Apex",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		sess, err := newSession()
+		if err != nil {
+			return err
+		}
+
+		// create tooling client
+		toolingSession = sess
+		toolingClient = toolingapi.NewClient(sess)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(apexCmd)
+}
@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// apexLogsCmd represents the logs command
+var apexLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "The logs command retrieves Apex debug logs",
+}
+
+func init() {
+	apexCmd.AddCommand(apexLogsCmd)
+}
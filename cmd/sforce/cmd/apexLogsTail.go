@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"syscall"
+	"time"
+
+	toolingapi "github.com/Laugusti/go-sforce/api/tooling"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsTailUser         string
+	logsTailGrep         string
+	logsTailDebugLevel   string
+	logsTailApexCode     string
+	logsTailDuration     time.Duration
+	logsTailPollInterval time.Duration
+)
+
+// apexLogsTailCmd represents the tail command
+var apexLogsTailCmd = &cobra.Command{
+	Use:   "tail",
+	Args:  cobra.NoArgs,
+	Short: "Streams new Apex debug logs of a user",
+	Long: `Sets a trace flag for the user (the logged in user by default) and streams the new
+debug logs of the user to standard output until interrupted. When grep is specified, only
+the log lines matching the regular expression are written.
+The trace flag is refreshed before it expires. On exit, the trace flag is deleted if it
+was created by the command, otherwise its debug level, start and expiration dates are
+restored.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var grep *regexp.Regexp
+		if logsTailGrep != "" {
+			re, err := regexp.Compile(logsTailGrep)
+			exitIfError("TailLogs", err)
+			grep = re
+		}
+
+		// get user id from session
+		userID := logsTailUser
+		if userID == "" {
+			if !toolingSession.HasToken() {
+				exitIfError("TailLogs", toolingSession.Login())
+			}
+			userID = toolingSession.UserID()
+		}
+
+		// stop on interrupt
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sig
+			cancel()
+		}()
+
+		// set trace flag
+		traceFlagInput := &toolingapi.SetTraceFlagInput{
+			TracedEntityID: userID,
+			DebugLevel: &toolingapi.DebugLevel{
+				DeveloperName: logsTailDebugLevel,
+				ApexCode:      logsTailApexCode,
+				ApexProfiling: "INFO",
+				Callout:       "INFO",
+				Database:      "INFO",
+				System:        "DEBUG",
+				Validation:    "INFO",
+				Visualforce:   "INFO",
+				Workflow:      "INFO",
+			},
+			Duration: logsTailDuration,
+		}
+		traceFlag, err := toolingClient.SetTraceFlag(ctx, traceFlagInput)
+		exitIfError("SetTraceFlag", err)
+		fmt.Fprintf(os.Stderr, "Tailing debug logs of user %s\n", userID)
+
+		err = tailLogs(ctx, userID, grep, traceFlagInput, traceFlag.ExpirationDate)
+
+		// remove trace flag created by the command, or restore the existing trace flag
+		if traceFlag.Created {
+			_, delErr := toolingClient.DeleteSObject(&toolingapi.DeleteSObjectInput{
+				SObjectName: "TraceFlag",
+				SObjectID:   traceFlag.TraceFlagID,
+			})
+			exitIfError("DeleteTraceFlag", delErr)
+		} else {
+			_, updErr := toolingClient.UpdateSObject(&toolingapi.UpdateSObjectInput{
+				SObjectName: "TraceFlag",
+				SObjectID:   traceFlag.TraceFlagID,
+				SObject:     traceFlag.Previous,
+			})
+			exitIfError("RestoreTraceFlag", updErr)
+		}
+		exitIfError("TailLogs", err)
+	},
+}
+
+// tailLogs polls for the new debug logs of the user and writes them to stdout until the
+// context is done. The trace flag is refreshed before it expires.
+func tailLogs(ctx context.Context, userID string, grep *regexp.Regexp,
+	traceFlagInput *toolingapi.SetTraceFlagInput, expiration time.Time) error {
+	since, seen, err := latestLogs(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for {
+		// refresh trace flag
+		if time.Until(expiration) < traceFlagInput.Duration/2 {
+			out, err := toolingClient.SetTraceFlag(ctx, traceFlagInput)
+			if err != nil {
+				return err
+			}
+			expiration = out.ExpirationDate
+		}
+
+		// write new logs
+		out, err := toolingClient.ListApexLogs(ctx, &toolingapi.ListApexLogsInput{
+			UserID: userID,
+			Since:  since,
+		})
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// logs since the last start time are listed again, skip the logs already written
+		listed := make(map[string]bool)
+		for _, log := range out.Logs {
+			listed[log.ID] = true
+			if seen[log.ID] {
+				continue
+			}
+			if err := writeLog(log, grep); err != nil {
+				return err
+			}
+			if log.StartTime.After(since) {
+				since = log.StartTime
+			}
+		}
+		seen = listed
+
+		// wait for next poll
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logsTailPollInterval):
+		}
+	}
+}
+
+// latestLogs returns the start time of the newest debug log of the user and the ids of the
+// logs started at that time, so that only the logs started afterwards are written. The time
+// is from the server, since the local clock may differ. The time is zero if the user has no
+// logs.
+func latestLogs(ctx context.Context, userID string) (time.Time, map[string]bool, error) {
+	seen := make(map[string]bool)
+	newest, err := toolingClient.ListApexLogs(ctx, &toolingapi.ListApexLogsInput{
+		UserID:     userID,
+		Limit:      1,
+		Descending: true,
+	})
+	if err != nil || len(newest.Logs) == 0 {
+		return time.Time{}, seen, err
+	}
+	since := newest.Logs[0].StartTime
+	out, err := toolingClient.ListApexLogs(ctx, &toolingapi.ListApexLogsInput{
+		UserID: userID,
+		Since:  since,
+	})
+	if err != nil {
+		return time.Time{}, seen, err
+	}
+	for _, log := range out.Logs {
+		seen[log.ID] = true
+	}
+	return since, seen, nil
+}
+
+// writeLog writes the debug log to stdout. Only the lines matching grep are written if grep
+// is not nil, and nothing is written if no line matches.
+func writeLog(log *toolingapi.ApexLog, grep *regexp.Regexp) error {
+	out, err := toolingClient.GetApexLogBody(&toolingapi.GetApexLogBodyInput{LogID: log.ID})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = out.Body.Close() }()
+
+	header := fmt.Sprintf("=== %s %s %s %s (%dms)\n", log.ID,
+		log.StartTime.Local().Format(time.RFC3339), log.Operation, log.Status,
+		log.DurationMilliseconds)
+	if grep == nil {
+		fmt.Print(header)
+		_, err := io.Copy(os.Stdout, out.Body)
+		fmt.Println()
+		return err
+	}
+
+	scanner := bufio.NewScanner(out.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if !grep.Match(scanner.Bytes()) {
+			continue
+		}
+		if header != "" {
+			fmt.Print(header)
+			header = ""
+		}
+		fmt.Println(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read log %s: %v", log.ID, err)
+	}
+	return nil
+}
+
+func init() {
+	apexLogsCmd.AddCommand(apexLogsTailCmd)
+	apexLogsTailCmd.Flags().StringVarP(&logsTailUser, "user", "u", "", "Specify the id of the user to trace (default is the logged in user)")
+	apexLogsTailCmd.Flags().StringVarP(&logsTailGrep, "grep", "g", "", "Only write the log lines matching the regular expression")
+	apexLogsTailCmd.Flags().StringVar(&logsTailDebugLevel, "debug-level", "SforceCLI", "Specify the developer name of the debug level to create or update")
+	apexLogsTailCmd.Flags().StringVar(&logsTailApexCode, "apex-code", "FINEST", "Specify the log level of the Apex code category")
+	apexLogsTailCmd.Flags().DurationVar(&logsTailDuration, "duration", time.Hour, "Specify how long the trace flag is set before it is refreshed (at most 24h)")
+	apexLogsTailCmd.Flags().DurationVar(&logsTailPollInterval, "poll-interval", 5*time.Second, "Specify how often new logs are polled")
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// apexRestCmd represents the apex command
+var apexRestCmd = &cobra.Command{
+	Use:   "apex <method> <path> [<file>]",
+	Args:  cobra.RangeArgs(2, 3),
+	Short: "Invokes the custom Apex REST endpoint using the method and path",
+	Long: `Invokes the custom Apex REST endpoint using the method and the path relative to
+/services/apexrest/ (e.g. Account/001?fields=Name). The request body is read from the json
+file. When file is -, read standard input.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// split query from path
+		apexPath, rawQuery := args[1], ""
+		if i := strings.Index(apexPath, "?"); i >= 0 {
+			apexPath, rawQuery = apexPath[:i], apexPath[i+1:]
+		}
+		query, err := url.ParseQuery(rawQuery)
+		exitIfError("ApexRest", err)
+
+		// unmarshal file to body
+		var body interface{}
+		if len(args) == 3 {
+			unmarshalJSONFile("ApexRest", args[2], &body)
+		}
+
+		// do api request
+		var out interface{}
+		err = restClient.ApexRest(args[0], apexPath, query, body, &out)
+		exitIfError("ApexRest", err)
+
+		// write response to stdout
+		if out != nil {
+			marshalJSONToStdout("ApexRest", out)
+		}
+	},
+}
+
+func init() {
+	restCmd.AddCommand(apexRestCmd)
+}
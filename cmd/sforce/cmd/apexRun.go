@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"io/ioutil"
+
+	toolingapi "github.com/Laugusti/go-sforce/api/tooling"
+	"github.com/spf13/cobra"
+)
+
+// apexRunCmd represents the run command
+var apexRunCmd = &cobra.Command{
+	Use:   "run [<file>]",
+	Args:  cobra.RangeArgs(0, 1),
+	Short: "Executes the anonymous Apex in the file",
+	Long: `Compiles and executes the anonymous Apex in the file.
+With no file or when file is -, read standard input. The URL encoded Apex cannot be
+longer than 16,000 bytes.
+Exits with a non-zero status if the Apex fails to compile or throws an exception.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// get apex from file or stdin
+		apex := ""
+		if len(args) == 1 && args[0] != "-" {
+			b, err := ioutil.ReadFile(args[0])
+			exitIfError("ExecuteAnonymous", err)
+			apex = string(b)
+		} else {
+			apex = readAllStdin("ExecuteAnonymous")
+		}
+
+		// do api request
+		out, err := toolingClient.ExecuteAnonymous(&toolingapi.ExecuteAnonymousInput{
+			ApexBody: apex,
+		})
+		exitIfError("ExecuteAnonymous", err)
+
+		// write result to stdout
+		marshalJSONToStdout("ExecuteAnonymous", out.Result)
+		exitIfError("ExecuteAnonymous", out.Result.Err())
+	},
+}
+
+func init() {
+	apexCmd.AddCommand(apexRunCmd)
+}
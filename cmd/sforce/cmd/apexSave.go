@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	toolingapi "github.com/Laugusti/go-sforce/api/tooling"
+	"github.com/spf13/cobra"
+)
+
+var (
+	apexSaveCheckOnly    bool
+	apexSavePollInterval time.Duration
+)
+
+// apexSaveCmd represents the save command
+var apexSaveCmd = &cobra.Command{
+	Use:   "save <file>...",
+	Args:  cobra.MinimumNArgs(1),
+	Short: "Compiles and saves the Apex class and trigger files",
+	Long: `Compiles and saves the Apex class (.cls) and trigger (.trigger) files. The name of
+the class or trigger is the file name without the extension, and it must already exist.
+When check only is specified, the files are compiled but not saved.
+Exits with a non-zero status if the files fail to compile.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// read members from files
+		members := make([]*toolingapi.ApexMember, len(args))
+		for i, file := range args {
+			ext := filepath.Ext(file)
+			member := &toolingapi.ApexMember{Name: strings.TrimSuffix(filepath.Base(file), ext)}
+			switch ext {
+			case ".cls":
+				member.Type = toolingapi.MemberTypeApexClass
+			case ".trigger":
+				member.Type = toolingapi.MemberTypeApexTrigger
+			default:
+				exitIfError("SaveApex", fmt.Errorf("unknown apex file extension %q", ext))
+			}
+			b, err := ioutil.ReadFile(file)
+			exitIfError("SaveApex", err)
+			member.Body = string(b)
+			members[i] = member
+		}
+
+		// do api request
+		out, err := toolingClient.SaveApex(context.Background(), &toolingapi.SaveApexInput{
+			Members:      members,
+			CheckOnly:    apexSaveCheckOnly,
+			PollInterval: apexSavePollInterval,
+		})
+		exitIfError("SaveApex", err)
+
+		// write result to stdout
+		marshalJSONToStdout("SaveApex", out.Result)
+		exitIfError("SaveApex", out.Result.Err())
+	},
+}
+
+func init() {
+	apexCmd.AddCommand(apexSaveCmd)
+	apexSaveCmd.Flags().BoolVar(&apexSaveCheckOnly, "check-only", false, "Compile the files without saving them")
+	apexSaveCmd.Flags().DurationVar(&apexSavePollInterval, "poll-interval", time.Second, "Specify how often the deployment is polled")
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"time"
+
+	toolingapi "github.com/Laugusti/go-sforce/api/tooling"
+	"github.com/spf13/cobra"
+)
+
+var (
+	apexTestClasses      string
+	apexTestSuites       string
+	apexTestLevel        string
+	apexTestOutput       string
+	apexTestMinCoverage  float64
+	apexTestPollInterval time.Duration
+	apexTestTimeout      time.Duration
+)
+
+// apexTestReport is the json output of the test command. The errors are the test classes
+// that failed (e.g. did not compile) or were aborted, which have no test results.
+type apexTestReport struct {
+	JobID           string                          `json:"jobId"`
+	Tests           int                             `json:"tests"`
+	Failures        int                             `json:"failures"`
+	Errors          int                             `json:"errors"`
+	Results         []*toolingapi.ApexTestResult    `json:"results"`
+	ClassErrors     []*toolingapi.ApexTestQueueItem `json:"classErrors"`
+	CoveragePercent float64                         `json:"coveragePercent"`
+	Coverage        []*toolingapi.ApexCodeCoverage  `json:"coverage"`
+}
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is the test suite of an Apex test class.
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+	runTime  int
+}
+
+// junitTestCase is the test case of an Apex test method.
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+}
+
+// junitFailure is the failure or error message and stack trace of a test case.
+type junitFailure struct {
+	Message    string `xml:"message,attr"`
+	Type       string `xml:"type,attr"`
+	StackTrace string `xml:",chardata"`
+}
+
+// apexTestCmd represents the test command
+var apexTestCmd = &cobra.Command{
+	Use:   "test",
+	Args:  cobra.NoArgs,
+	Short: "Runs the Apex tests and reports the results",
+	Long: `Runs the Apex test classes and suites, or every test of the test level, and waits
+for the tests to complete. The test results and the code coverage are written to standard
+output as json or JUnit XML. The code coverage is the aggregate coverage of the classes and
+triggers covered by the test classes of the run (ApexCodeCoverageAggregate, filtered on the
+ApexCodeCoverage of the run's test classes).
+Exits with a non-zero status if a test fails, a test class fails to run (e.g. does not
+compile) or the code coverage is below the minimum.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if apexTestOutput != "json" && apexTestOutput != "junit" {
+			exitIfError("RunTestsAsynchronous", fmt.Errorf("invalid output format %q", apexTestOutput))
+		}
+		ctx := context.Background()
+		if apexTestTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, apexTestTimeout)
+			defer cancel()
+		}
+
+		// enqueue tests
+		run, err := toolingClient.RunTestsAsynchronous(&toolingapi.RunTestsAsynchronousInput{
+			ClassNames: splitString(apexTestClasses, ","),
+			SuiteNames: splitString(apexTestSuites, ","),
+			TestLevel:  apexTestLevel,
+		})
+		exitIfError("RunTestsAsynchronous", err)
+		fmt.Fprintf(os.Stderr, "Waiting for test run %s\n", run.JobID)
+
+		// wait for tests to complete
+		tests, err := toolingClient.WaitForTests(ctx, &toolingapi.WaitForTestsInput{
+			JobID:        run.JobID,
+			PollInterval: apexTestPollInterval,
+		})
+		exitIfError("WaitForTests", err)
+		testClassIDs := make([]string, len(tests.QueueItems))
+		for i, item := range tests.QueueItems {
+			testClassIDs[i] = item.ApexClassID
+		}
+		coverage, err := toolingClient.GetCodeCoverage(ctx, &toolingapi.GetCodeCoverageInput{
+			TestClassIDs: testClassIDs,
+		})
+		exitIfError("GetCodeCoverage", err)
+
+		// write report to stdout
+		report := newApexTestReport(run.JobID, tests, coverage)
+		if apexTestOutput == "junit" {
+			writeJUnitReport(newJUnitReport(report))
+		} else {
+			marshalJSONToStdout("RunTestsAsynchronous", report)
+		}
+
+		// check failures and coverage
+		failed := false
+		for _, item := range report.ClassErrors {
+			fmt.Fprintf(os.Stderr, "Test class %s %s: %s\n", item.ClassName(), item.Status,
+				item.ExtendedStatus)
+			failed = true
+		}
+		if report.Failures > 0 {
+			fmt.Fprintf(os.Stderr, "%d of %d tests failed\n", report.Failures, report.Tests)
+			failed = true
+		}
+		if apexTestMinCoverage > 0 && coverage.Percent() < apexTestMinCoverage {
+			fmt.Fprintf(os.Stderr, "Code coverage %.1f%% is below %g%%\n", coverage.Percent(),
+				apexTestMinCoverage)
+			failed = true
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// newApexTestReport returns the report of the test run, counting the failed test methods and
+// the test classes that failed to run.
+func newApexTestReport(jobID string, tests *toolingapi.WaitForTestsOutput,
+	coverage *toolingapi.GetCodeCoverageOutput) *apexTestReport {
+	report := &apexTestReport{
+		JobID:           jobID,
+		Tests:           len(tests.Results),
+		Results:         tests.Results,
+		ClassErrors:     []*toolingapi.ApexTestQueueItem{},
+		CoveragePercent: coverage.Percent(),
+		Coverage:        coverage.Coverage,
+	}
+	for _, result := range tests.Results {
+		if result.Failed() {
+			report.Failures++
+		}
+	}
+	for _, item := range tests.QueueItems {
+		if item.Failed() {
+			report.ClassErrors = append(report.ClassErrors, item)
+			report.Errors++
+		}
+	}
+	return report
+}
+
+// newJUnitReport returns the test results as JUnit test suites, grouped by test class. A test
+// class that failed to run is reported as a test case with an error.
+func newJUnitReport(report *apexTestReport) *junitTestSuites {
+	junit := &junitTestSuites{Name: report.JobID}
+	suites := make(map[string]*junitTestSuite)
+	suiteFor := func(className string) *junitTestSuite {
+		s, ok := suites[className]
+		if !ok {
+			s = &junitTestSuite{Name: className}
+			suites[className] = s
+			junit.Suites = append(junit.Suites, s)
+		}
+		return s
+	}
+	totalTime := 0
+	for _, result := range report.Results {
+		suite := suiteFor(result.ClassName())
+		tc := &junitTestCase{ClassName: result.ClassName(), Name: result.MethodName,
+			Time: junitTime(result.RunTime)}
+		switch {
+		case result.Failed():
+			tc.Failure = &junitFailure{Message: result.Message, Type: result.Outcome,
+				StackTrace: result.StackTrace}
+			suite.Failures++
+			junit.Failures++
+		case result.Outcome == "Skip":
+			tc.Skipped = &struct{}{}
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, tc)
+		suite.Tests++
+		suite.runTime += result.RunTime
+		junit.Tests++
+		totalTime += result.RunTime
+	}
+	for _, item := range report.ClassErrors {
+		suite := suiteFor(item.ClassName())
+		suite.Cases = append(suite.Cases, &junitTestCase{ClassName: item.ClassName(),
+			Name: item.ClassName(), Time: junitTime(0),
+			Error: &junitFailure{Message: item.ExtendedStatus, Type: item.Status}})
+		suite.Tests++
+		suite.Errors++
+		junit.Tests++
+		junit.Errors++
+	}
+	for _, suite := range junit.Suites {
+		suite.Time = junitTime(suite.runTime)
+	}
+	junit.Time = junitTime(totalTime)
+	return junit
+}
+
+// writeJUnitReport writes the JUnit test suites to stdout as XML.
+func writeJUnitReport(junit *junitTestSuites) {
+	_, err := os.Stdout.WriteString(xml.Header)
+	exitIfError("RunTestsAsynchronous", err)
+	enc := xml.NewEncoder(os.Stdout)
+	enc.Indent("", "\t")
+	exitIfError("RunTestsAsynchronous", enc.Encode(junit))
+	fmt.Println()
+}
+
+// junitTime returns the run time in milliseconds as JUnit seconds.
+func junitTime(ms int) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+func init() {
+	apexCmd.AddCommand(apexTestCmd)
+	apexTestCmd.Flags().StringVarP(&apexTestClasses, "classes", "c", "", "Specify a comma-separated list of test classes to run")
+	apexTestCmd.Flags().StringVarP(&apexTestSuites, "suites", "s", "", "Specify a comma-separated list of test suites to run")
+	apexTestCmd.Flags().StringVarP(&apexTestLevel, "test-level", "l", "", "Specify the test level (RunLocalTests or RunAllTestsInOrg) when no classes or suites are specified")
+	apexTestCmd.Flags().StringVarP(&apexTestOutput, "output", "o", "json", "Specify the output format (json or junit)")
+	apexTestCmd.Flags().Float64Var(&apexTestMinCoverage, "min-coverage", 0, "Exit with a non-zero status if the code coverage percentage is below the minimum")
+	apexTestCmd.Flags().DurationVar(&apexTestPollInterval, "poll-interval", 5*time.Second, "Specify how often the test run is polled")
+	apexTestCmd.Flags().DurationVar(&apexTestTimeout, "timeout", 0, "Stop waiting for the test run after the timeout")
+}
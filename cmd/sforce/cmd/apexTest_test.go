@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"testing"
+
+	toolingapi "github.com/Laugusti/go-sforce/api/tooling"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApexTestReport(t *testing.T) {
+	classRef := func(name string) *toolingapi.ApexClassRef {
+		return &toolingapi.ApexClassRef{Name: name}
+	}
+	tests := &toolingapi.WaitForTestsOutput{
+		QueueItems: []*toolingapi.ApexTestQueueItem{
+			{ApexClass: classRef("PassTest"), Status: "Completed"},
+			{ApexClass: classRef("BrokenTest"), Status: "Failed",
+				ExtendedStatus: "Compile error at line 3"},
+			{ApexClass: classRef("AbortedTest"), Status: "Aborted"},
+		},
+		Results: []*toolingapi.ApexTestResult{
+			{ApexClass: classRef("PassTest"), MethodName: "testOk", Outcome: "Pass", RunTime: 20},
+			{ApexClass: classRef("PassTest"), MethodName: "testBad", Outcome: "Fail", RunTime: 30,
+				Message: "Assertion failed"},
+		},
+	}
+	coverage := &toolingapi.GetCodeCoverageOutput{}
+
+	report := newApexTestReport("707", tests, coverage)
+	assert.Equal(t, 2, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 2, report.Errors)
+	assert.Equal(t, []*toolingapi.ApexTestQueueItem{tests.QueueItems[1], tests.QueueItems[2]},
+		report.ClassErrors)
+
+	junit := newJUnitReport(report)
+	assert.Equal(t, 4, junit.Tests)
+	assert.Equal(t, 1, junit.Failures)
+	assert.Equal(t, 2, junit.Errors)
+	assert.Equal(t, "0.050", junit.Time)
+	if assert.Len(t, junit.Suites, 3) {
+		assert.Equal(t, "PassTest", junit.Suites[0].Name)
+		assert.Equal(t, 2, junit.Suites[0].Tests)
+		assert.Equal(t, 1, junit.Suites[0].Failures)
+		broken := junit.Suites[1]
+		assert.Equal(t, "BrokenTest", broken.Name)
+		assert.Equal(t, 1, broken.Errors)
+		if assert.Len(t, broken.Cases, 1) {
+			assert.Equal(t, &junitFailure{Message: "Compile error at line 3", Type: "Failed"},
+				broken.Cases[0].Error)
+		}
+	}
+}
+
+func TestApexTestReportFailedClassWithoutResults(t *testing.T) {
+	tests := &toolingapi.WaitForTestsOutput{
+		QueueItems: []*toolingapi.ApexTestQueueItem{
+			{ApexClass: &toolingapi.ApexClassRef{Name: "BrokenTest"}, Status: "Failed",
+				ExtendedStatus: "Compile error at line 3"},
+		},
+	}
+
+	report := newApexTestReport("707", tests, &toolingapi.GetCodeCoverageOutput{})
+	assert.Equal(t, 0, report.Tests)
+	assert.Equal(t, 0, report.Failures)
+	assert.Equal(t, 1, report.Errors)
+	assert.Len(t, report.ClassErrors, 1)
+
+	junit := newJUnitReport(report)
+	assert.Equal(t, 1, junit.Tests)
+	assert.Equal(t, 1, junit.Errors)
+	if assert.Len(t, junit.Suites, 1) && assert.Len(t, junit.Suites[0].Cases, 1) {
+		tc := junit.Suites[0].Cases[0]
+		assert.Equal(t, "BrokenTest", tc.Name)
+		assert.Nil(t, tc.Failure)
+		assert.Equal(t, "Compile error at line 3", tc.Error.Message)
+	}
+}
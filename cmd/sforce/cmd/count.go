@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/spf13/cobra"
+)
+
+// countCmd represents the count command
+var countCmd = &cobra.Command{
+	Use:   "count [<name>...]",
+	Short: "Retrieves the record counts of the SObjects",
+	Long: `Retrieves the approximate record counts of the SObjects using the Object Names.
+With no names, retrieves the record counts of all SObjects.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// create api input
+		input := &restapi.RecordCountInput{
+			SObjectNames: args,
+		}
+
+		// do api request
+		out, err := restClient.RecordCount(input)
+		exitIfError("RecordCount", err)
+
+		// write counts to stdout
+		marshalJSONToStdout("RecordCount", out.Counts)
+	},
+}
+
+func init() {
+	restCmd.AddCommand(countCmd)
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"path/filepath"
+	"time"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/spf13/cobra"
+)
+
+var describeCacheTTL time.Duration
+
+// describeCmd represents the describe command
+var describeCmd = &cobra.Command{
+	Use:   "describe [<name>]",
+	Args:  cobra.RangeArgs(0, 1),
+	Short: "Describes the SObject metadata using the Object Name",
+	Long: `Describes the SObject metadata (fields, child relationships, record types and urls)
+using the Object Name. With no name, lists the SObjects available in the organization.
+When a cache ttl is specified, the result is cached in $HOME/.sforce/cache and revalidated
+after the ttl.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if describeCacheTTL > 0 {
+			describeWithCache(args)
+			return
+		}
+
+		// no name, describe global
+		if len(args) == 0 {
+			out, err := restClient.DescribeGlobal(&restapi.DescribeGlobalInput{})
+			exitIfError("DescribeGlobal", err)
+			marshalJSONToStdout("DescribeGlobal", out.Result)
+			return
+		}
+
+		// create api input
+		input := &restapi.DescribeSObjectInput{
+			SObjectName: args[0],
+		}
+
+		// do api request
+		out, err := restClient.DescribeSObject(input)
+		exitIfError("DescribeSObject", err)
+
+		// write result to stdout
+		marshalJSONToStdout("DescribeSObject", out.Result)
+	},
+}
+
+// describeWithCache writes the describe result from the describe cache to stdout.
+func describeWithCache(args []string) {
+	cfgHome, err := defaultCfgHome()
+	exitIfError("DescribeCache", err)
+	cache := restapi.NewDescribeCache(restClient, describeCacheTTL,
+		filepath.Join(cfgHome, "cache"))
+
+	// no name, describe global
+	if len(args) == 0 {
+		result, err := cache.DescribeGlobal()
+		exitIfError("DescribeGlobal", err)
+		marshalJSONToStdout("DescribeGlobal", result)
+		return
+	}
+
+	result, err := cache.DescribeSObject(args[0])
+	exitIfError("DescribeSObject", err)
+	marshalJSONToStdout("DescribeSObject", result)
+}
+
+func init() {
+	restCmd.AddCommand(describeCmd)
+	describeCmd.Flags().DurationVar(&describeCacheTTL, "cache-ttl", 0, "Cache the result for the specified duration (e.g. 1h)")
+}
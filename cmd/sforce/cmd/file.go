@@ -0,0 +1,15 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// fileCmd represents the file command
+var fileCmd = &cobra.Command{
+	Use:   "file",
+	Short: "The file command uploads and downloads SObject blob fields",
+}
+
+func init() {
+	restCmd.AddCommand(fileCmd)
+}
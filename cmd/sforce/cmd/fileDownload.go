@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/spf13/cobra"
+)
+
+// fileDownloadCmd represents the download command
+var fileDownloadCmd = &cobra.Command{
+	Use:   "download <name> <id> <field> [<file>]",
+	Args:  cobra.RangeArgs(3, 4),
+	Short: "Downloads the blob field of the SObject using the Object Name, Object ID and field",
+	Long: `Downloads the blob field (e.g. ContentVersion VersionData, Attachment Body) of the
+SObject using the Object Name, Object ID and field.
+With no file or when file is -, write to standard output.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// create api input
+		input := &restapi.GetSObjectBlobInput{
+			SObjectName: args[0],
+			SObjectID:   args[1],
+			BlobField:   args[2],
+		}
+
+		// do api request
+		out, err := restClient.GetSObjectBlob(input)
+		exitIfError("GetSObjectBlob", err)
+		defer func() { _ = out.Body.Close() }()
+
+		// write blob to file or stdout
+		w := os.Stdout
+		if len(args) == 4 && args[3] != "-" {
+			f, err := os.Create(args[3])
+			exitIfError("GetSObjectBlob", err)
+			defer func() { _ = f.Close() }()
+			w = f
+		}
+		_, err = io.Copy(w, out.Body)
+		exitIfError("GetSObjectBlob", err)
+	},
+}
+
+func init() {
+	fileCmd.AddCommand(fileDownloadCmd)
+}
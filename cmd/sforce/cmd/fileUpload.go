@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fileUploadID   string
+	fileUploadData string
+)
+
+// fileUploadCmd represents the upload command
+var fileUploadCmd = &cobra.Command{
+	Use:   "upload <name> <field> <file>",
+	Args:  cobra.ExactArgs(3),
+	Short: "Uploads the file to the blob field of a SObject using the Object Name and field",
+	Long: `Uploads the file to the blob field (e.g. ContentVersion VersionData, Attachment Body)
+of a new SObject using the Object Name and field. The other fields of the SObject are read
+from the data file. When an id is specified, the existing SObject is updated instead.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// unmarshal data file to sobject
+		var sobj restapi.SObject
+		if fileUploadData != "" {
+			unmarshalJSONFile("UploadFile", fileUploadData, &sobj)
+		}
+
+		// open file
+		f, err := os.Open(args[2])
+		exitIfError("UploadFile", err)
+		defer func() { _ = f.Close() }()
+
+		// update existing sobject
+		if fileUploadID != "" {
+			input := &restapi.UpdateSObjectWithBlobInput{
+				SObjectName: args[0],
+				SObjectID:   fileUploadID,
+				SObject:     sobj,
+				BlobField:   args[1],
+				FileName:    filepath.Base(args[2]),
+				Blob:        f,
+			}
+			_, err := restClient.UpdateSObjectWithBlob(input)
+			exitIfError("UpdateSObjectWithBlob", err)
+			fmt.Printf("Updated %s object with Id %q\n", args[0], fileUploadID)
+			return
+		}
+
+		// create api input
+		input := &restapi.CreateSObjectWithBlobInput{
+			SObjectName: args[0],
+			SObject:     sobj,
+			BlobField:   args[1],
+			FileName:    filepath.Base(args[2]),
+			Blob:        f,
+		}
+
+		// do api request
+		out, err := restClient.CreateSObjectWithBlob(input)
+		exitIfError("CreateSObjectWithBlob", err)
+
+		// write result to stdout
+		marshalJSONToStdout("CreateSObjectWithBlob", out.Result)
+	},
+}
+
+func init() {
+	fileCmd.AddCommand(fileUploadCmd)
+	fileUploadCmd.Flags().StringVar(&fileUploadID, "id", "", "Update the SObject with the specified Id")
+	fileUploadCmd.Flags().StringVarP(&fileUploadData, "data", "d", "", "Specify the data file with the other SObject fields (- for standard input)")
+}
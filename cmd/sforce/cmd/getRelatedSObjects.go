@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	relatedFields string
+	relatedAll    bool
+)
+
+// getRelatedSObjectsCmd represents the related command
+var getRelatedSObjectsCmd = &cobra.Command{
+	Use:   "related <name> <id> <relationship>",
+	Args:  cobra.ExactArgs(3),
+	Short: "Retrieves the related SObjects using the Object Name, Object ID and relationship name",
+	Long: `Retrieves the related SObjects using the Object Name, Object ID and relationship name.
+Returns the SObject for a lookup relationship (e.g. Owner) or the query result for a child
+relationship (e.g. Contacts). With the all flag, returns every child record.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// create api input
+		input := &restapi.GetRelatedSObjectsInput{
+			SObjectName:      args[0],
+			SObjectID:        args[1],
+			RelationshipName: args[2],
+			Fields:           splitString(relatedFields, ","),
+		}
+
+		// do api request
+		out, err := restClient.GetRelatedSObjects(input)
+		exitIfError("GetRelatedSObjects", err)
+
+		// lookup relationship
+		if out.Result == nil {
+			marshalJSONToStdout("GetRelatedSObjects", out.SObject)
+			return
+		}
+
+		// child relationship
+		if !relatedAll {
+			marshalJSONToStdout("GetRelatedSObjects", out.Result)
+			return
+		}
+		records := []restapi.SObject{}
+		it := restClient.QueryResultIterator(context.Background(), out.Result)
+		for it.Next() {
+			records = append(records, it.Record())
+		}
+		exitIfError("QueryMore", it.Err())
+		marshalJSONToStdout("GetRelatedSObjects", records)
+	},
+}
+
+func init() {
+	sobjectCmd.AddCommand(getRelatedSObjectsCmd)
+	getRelatedSObjectsCmd.Flags().StringVarP(&relatedFields, "fields", "f", "", "Specify the fields you want to retrieve")
+	getRelatedSObjectsCmd.Flags().BoolVarP(&relatedAll, "all", "a", false, "Retrieve every child record")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	limitsTable     bool
+	limitsThreshold float64
+)
+
+// limitsCmd represents the limits command
+var limitsCmd = &cobra.Command{
+	Use:   "limits",
+	Args:  cobra.NoArgs,
+	Short: "Lists the organization limits",
+	Long: `Lists the maximum and remaining allocations of the organization limits.
+When a threshold is specified, exits with a non-zero status if the remaining percentage
+of any limit is below the threshold.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// do api request
+		out, err := restClient.Limits(&restapi.LimitsInput{})
+		exitIfError("Limits", err)
+
+		// write limits to stdout
+		if limitsTable {
+			writeLimitsTable(out.Limits)
+		} else {
+			marshalJSONToStdout("Limits", out.Limits)
+		}
+
+		// check limits against threshold
+		if limitsThreshold <= 0 {
+			return
+		}
+		below := false
+		for _, name := range sortedLimitNames(out.Limits) {
+			limit := out.Limits[name]
+			if limit.PercentRemaining() < limitsThreshold {
+				fmt.Fprintf(os.Stderr, "Limit %s is below %g%%: %d of %d remaining\n",
+					name, limitsThreshold, limit.Remaining, limit.Max)
+				below = true
+			}
+		}
+		if below {
+			os.Exit(1)
+		}
+	},
+}
+
+// writeLimitsTable writes the limits to stdout as a table sorted by name.
+func writeLimitsTable(limits map[string]*restapi.Limit) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tMAX\tREMAINING\tREMAINING %")
+	for _, name := range sortedLimitNames(limits) {
+		limit := limits[name]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\n", name, limit.Max, limit.Remaining,
+			limit.PercentRemaining())
+	}
+	exitIfError("Limits", w.Flush())
+}
+
+func sortedLimitNames(limits map[string]*restapi.Limit) []string {
+	names := make([]string, 0, len(limits))
+	for name := range limits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	restCmd.AddCommand(limitsCmd)
+	limitsCmd.Flags().BoolVarP(&limitsTable, "table", "t", false, "Print the limits as a table")
+	limitsCmd.Flags().Float64Var(&limitsThreshold, "threshold", 0, "Exit with a non-zero status if any limit has less than the specified percentage remaining")
+}
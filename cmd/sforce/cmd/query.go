@@ -1,17 +1,32 @@
 package cmd
 
 import (
+	"errors"
+
 	restapi "github.com/Laugusti/go-sforce/api/rest"
 	"github.com/spf13/cobra"
 )
 
+var (
+	queryAllRows bool
+	queryExplain bool
+)
+
 // queryCmd represents the query command
 var queryCmd = &cobra.Command{
-	Use:   "query [<query>]",
-	Args:  cobra.RangeArgs(0, 1),
+	Use: "query [<query>]",
+	Args: func(cmd *cobra.Command, args []string) error {
+		// the query plans cannot include deleted and archived records
+		if queryExplain && queryAllRows {
+			return errors.New("--explain cannot be used with --all-rows")
+		}
+		return cobra.RangeArgs(0, 1)(cmd, args)
+	},
 	Short: "Executes the specified SOQL query",
 	Long: `Executes the specified SOQL query.
-With no query or when query is -, read standard input`,
+With no query or when query is -, read standard input.
+When explain is specified, the query is not executed and the query plans are
+listed instead, starting with the cheapest. Explain cannot be used with all rows.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// get query from args or stdin
 		query := ""
@@ -21,6 +36,25 @@ With no query or when query is -, read standard input`,
 			query = readAllStdin("Query")
 		}
 
+		// list query plans ranked by cost
+		if queryExplain {
+			out, err := restClient.Explain(&restapi.ExplainInput{Query: query})
+			exitIfError("Explain", err)
+			marshalJSONToStdout("Explain", out.Result.ByCost())
+			return
+		}
+
+		// include deleted and archived records
+		if queryAllRows {
+			input := &restapi.QueryAllRowsInput{
+				Query: query,
+			}
+			out, err := restClient.QueryAllRows(input)
+			exitIfError("QueryAllRows", err)
+			marshalJSONToStdout("QueryAllRows", out.Result)
+			return
+		}
+
 		// create api input
 		input := &restapi.QueryInput{
 			Query: query,
@@ -37,4 +71,6 @@ With no query or when query is -, read standard input`,
 
 func init() {
 	restCmd.AddCommand(queryCmd)
+	queryCmd.Flags().BoolVar(&queryAllRows, "all-rows", false, "Include deleted and archived records")
+	queryCmd.Flags().BoolVar(&queryExplain, "explain", false, "List the query plans ranked by cost instead of executing the query")
 }
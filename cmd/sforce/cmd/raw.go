@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var rawData string
+
+// rawCmd represents the raw command
+var rawCmd = &cobra.Command{
+	Use:   "raw <method> <path>",
+	Args:  cobra.ExactArgs(2),
+	Short: "Sends a request to the Salesforce API using the method and path",
+	Long: `Sends a request to the Salesforce API using the method and the api path
+(e.g. /services/data/v50.0/limits?a=b) and writes the response body to standard output.
+The request body is the data, or is read from the file when the data starts with @.
+When the data is @-, read standard input.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// split query from path
+		apiPath, rawQuery := args[1], ""
+		if i := strings.Index(apiPath, "?"); i >= 0 {
+			apiPath, rawQuery = apiPath[:i], apiPath[i+1:]
+		}
+		query, err := url.ParseQuery(rawQuery)
+		exitIfError("Do", err)
+
+		// read request body
+		var body interface{}
+		switch {
+		case rawData == "@-":
+			body = []byte(readAllStdin("Do"))
+		case strings.HasPrefix(rawData, "@"):
+			b, err := ioutil.ReadFile(rawData[1:])
+			exitIfError("Do", err)
+			body = b
+		case rawData != "":
+			body = []byte(rawData)
+		}
+
+		// do api request
+		resp, err := restClient.Do(args[0], apiPath, query, body)
+		exitIfError("Do", err)
+
+		// write response to stdout, indenting json
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, resp.Body, "", "\t"); err == nil {
+			buf.WriteByte('\n')
+		} else {
+			buf.Reset()
+			buf.Write(resp.Body)
+		}
+		_, err = buf.WriteTo(os.Stdout)
+		exitIfError("Do", err)
+	},
+}
+
+func init() {
+	restCmd.AddCommand(rawCmd)
+	rawCmd.Flags().StringVarP(&rawData, "data", "d", "", "Specify the request body (@file to read from file, @- for standard input)")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	restapi "github.com/Laugusti/go-sforce/api/rest"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchParameterized  bool
+	searchIn             string
+	searchSObjects       string
+	searchFields         string
+	searchLimit          int
+	searchNoSpellCorrect bool
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search [<search>]",
+	Args:  cobra.RangeArgs(0, 1),
+	Short: "Executes the specified SOSL search",
+	Long: `Executes the specified SOSL search. The records are grouped by SObject type.
+With the parameterized flag, searches for the text without a SOSL statement.
+With no search or when search is -, read standard input`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// get search from args or stdin
+		search := ""
+		if len(args) == 1 && args[0] != "-" {
+			search = args[0]
+		} else {
+			search = readAllStdin("Search")
+		}
+
+		// search text without sosl
+		if searchParameterized {
+			input := &restapi.ParameterizedSearchInput{
+				Search:                 search,
+				In:                     searchIn,
+				Fields:                 splitString(searchFields, ","),
+				OverallLimit:           searchLimit,
+				DisableSpellCorrection: searchNoSpellCorrect,
+			}
+			for _, name := range splitString(searchSObjects, ",") {
+				input.SObjects = append(input.SObjects, &restapi.SearchSObject{Name: name})
+			}
+			out, err := restClient.ParameterizedSearch(input)
+			exitIfError("ParameterizedSearch", err)
+			marshalJSONToStdout("ParameterizedSearch", out.Result.ByType())
+			return
+		}
+
+		// create api input
+		input := &restapi.SearchInput{
+			Search: search,
+		}
+
+		// do api request
+		out, err := restClient.Search(input)
+		exitIfError("Search", err)
+
+		// write result to stdout
+		marshalJSONToStdout("Search", out.Result.ByType())
+	},
+}
+
+func init() {
+	restCmd.AddCommand(searchCmd)
+	searchCmd.Flags().BoolVarP(&searchParameterized, "parameterized", "p", false, "Search for the text without a SOSL statement")
+	searchCmd.Flags().StringVar(&searchIn, "in", "", "Specify the fields to search (ALL, NAME, EMAIL, PHONE or SIDEBAR) for a parameterized search")
+	searchCmd.Flags().StringVar(&searchSObjects, "sobjects", "", "Specify the SObjects to search for a parameterized search")
+	searchCmd.Flags().StringVarP(&searchFields, "fields", "f", "", "Specify the fields to return for a parameterized search")
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 0, "Specify the maximum number of records to return for a parameterized search")
+	searchCmd.Flags().BoolVar(&searchNoSpellCorrect, "no-spell-correction", false, "Disable spell correction for a parameterized search")
+}
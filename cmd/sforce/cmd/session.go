@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Laugusti/go-sforce/sforce/credentials"
+	"github.com/Laugusti/go-sforce/sforce/session"
+	"github.com/spf13/viper"
+)
+
+// newSession creates a Salesforce session from the configured credentials and config.
+func newSession() (*session.Session, error) {
+	missing := []string{}
+	// get creds
+	username := getConfigString(credsViper, usernameCfgName, &missing)
+	password := getConfigString(credsViper, passwordCfgName, &missing)
+	clientID := getConfigString(credsViper, clientIDCfgName, &missing)
+	clientSecret := getConfigString(credsViper, clientSecretCfgName, &missing)
+	// error on missing creds
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing credentials: %s."+
+			" You can configure by runnning \"sforce configure\"",
+			strings.Join(missing, ", "))
+	}
+
+	// get config
+	loginURL := getConfigString(configViper, loginURLCfgName, &missing)
+	apiVersion := getConfigString(configViper, apiVersionCfgName, &missing)
+	// error on missing config
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing configuration: %s."+
+			" You can configure by running \"sforce configure\"",
+			strings.Join(missing, ", "))
+	}
+
+	return session.New(loginURL, apiVersion,
+		credentials.New(username, password, clientID, clientSecret))
+}
+
+func getConfigString(v *viper.Viper, cfgName string, missing *[]string) string {
+	s := v.GetString(cfgName)
+	if s == "" {
+		*missing = append(*missing, cfgName)
+	}
+	return s
+}
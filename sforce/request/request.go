@@ -17,17 +17,21 @@ import (
 // ResultType is body type (json, xml, etc.) of the Request result.
 type ResultType int
 
-// result types
+// result types. For a StreamResult, the result must be a *io.ReadCloser that is set to the
+// response body. The caller is responsible for closing the body.
 const (
 	JSONResult ResultType = iota
 	XMLResult
+	StreamResult
 )
 
-// Operation represents an http operation.
+// Operation represents an http operation. Header values are set after the pre send
+// handlers are run, so they take precedence over headers set by the handlers.
 type Operation struct {
 	Method   string
 	APIPath  string
 	RawQuery string
+	Header   http.Header
 	Body     io.Reader
 }
 
@@ -49,12 +53,21 @@ type Request struct {
 	expect          *ResultExpectation
 	result          interface{}
 	preSendHandlers []func(*http.Request)
+	resp            *http.Response
 }
 
 // New creates a new Request.
 func New(sess *session.Session, op *Operation, expect *ResultExpectation,
 	result interface{}, preSendHandlers ...func(*http.Request)) *Request {
-	return &Request{sess, op, expect, result, preSendHandlers}
+	return &Request{sess: sess, op: op, expect: expect, result: result,
+		preSendHandlers: preSendHandlers}
+}
+
+// Response returns the http response received by Send. The response body has already been
+// consumed, unless the result type is StreamResult. Returns nil if Send has not been called
+// or the request failed.
+func (r *Request) Response() *http.Response {
+	return r.resp
 }
 
 // buildRequest creates a http.Request struct for the api path.
@@ -86,6 +99,10 @@ func (r *Request) buildRequest(op *Operation, preSendHandlers []func(*http.Reque
 	for _, h := range preSendHandlers {
 		h(req)
 	}
+	// set operation headers
+	for k, v := range op.Header {
+		req.Header[k] = v
+	}
 
 	return req, nil
 }
@@ -101,29 +118,39 @@ func (r *Request) Send() error {
 	if err != nil {
 		return fmt.Errorf("request failed: %v", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
 	// login and retry if unauthorized
 	if resp.StatusCode == http.StatusUnauthorized {
+		// close original body
+		_ = resp.Body.Close()
 		err := r.sess.Login()
 		if err != nil {
 			return err
 		}
 		// request body was consumed, resetting
-		body, err := req.GetBody()
-		if err != nil {
-			return fmt.Errorf("failed to get request body for retry: %v", err)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return fmt.Errorf("failed to get request body for retry: %v", err)
+			}
+			req.Body = body
+		} else if req.Body != nil {
+			return errors.New("failed to get request body for retry: body cannot be reset")
 		}
-		req.Body = body
 		// retry request
 		retryResp, err := r.sess.HTTPClient.Do(req)
 		if err != nil {
 			return fmt.Errorf("request failed: %v", err)
 		}
-		// close original body and update to new response
-		_ = resp.Body.Close()
 		resp = retryResp
 	}
+	r.resp = resp
+
+	// stream response body to caller
+	if r.expect.Type == StreamResult {
+		return streamResponse(resp, r.expect.StatusCodes, r.result)
+	}
+	defer func() { _ = resp.Body.Close() }()
 
 	// unmarshal response based on wanted type
 	switch r.expect.Type {
@@ -147,15 +174,12 @@ func unmarshalResponse(unmarshalFunc func([]byte, interface{}) error, resp *http
 	}
 	// return api error if status code is unexpected
 	if !isInSlice(resp.StatusCode, validCodes) {
-		var apiErr sforceerr.APIError
-		if err := unmarshalFunc(data, &[]*sforceerr.APIError{&apiErr}); err != nil || apiErr.ErrorCode == "" {
-			// failed to get api error
-			return fmt.Errorf("unexpected status code (want %v, got %d): %s",
-				validCodes, resp.StatusCode, data)
-		}
-		apiErr.ActualStatusCode = resp.StatusCode
-		apiErr.ExpectedStatusCodes = validCodes
-		return &apiErr
+		return unexpectedStatusError(unmarshalFunc, data, resp.StatusCode, validCodes)
+	}
+
+	// no body to unmarshal
+	if resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified {
+		return nil
 	}
 
 	// unmarshal to result
@@ -166,3 +190,38 @@ func unmarshalResponse(unmarshalFunc func([]byte, interface{}) error, resp *http
 	}
 	return nil
 }
+
+// streamResponse sets the result to the response body if the status code is expected.
+// Otherwise, the body is closed and the api error is returned.
+func streamResponse(resp *http.Response, validCodes []int, result interface{}) error {
+	if !isInSlice(resp.StatusCode, validCodes) {
+		defer func() { _ = resp.Body.Close() }()
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %v", err)
+		}
+		return unexpectedStatusError(json.Unmarshal, data, resp.StatusCode, validCodes)
+	}
+	rc, ok := result.(*io.ReadCloser)
+	if !ok || rc == nil {
+		_ = resp.Body.Close()
+		return fmt.Errorf("stream result must be a *io.ReadCloser, got %T", result)
+	}
+	*rc = resp.Body
+	return nil
+}
+
+// unexpectedStatusError returns the api error in the response body, or a generic error if the
+// body does not contain an api error.
+func unexpectedStatusError(unmarshalFunc func([]byte, interface{}) error, data []byte,
+	statusCode int, validCodes []int) error {
+	var apiErr sforceerr.APIError
+	if err := unmarshalFunc(data, &[]*sforceerr.APIError{&apiErr}); err != nil || apiErr.ErrorCode == "" {
+		// failed to get api error
+		return fmt.Errorf("unexpected status code (want %v, got %d): %s",
+			validCodes, statusCode, data)
+	}
+	apiErr.ActualStatusCode = statusCode
+	apiErr.ExpectedStatusCodes = validCodes
+	return &apiErr
+}
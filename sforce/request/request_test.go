@@ -2,7 +2,10 @@ package request
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/Laugusti/go-sforce/internal/testserver"
@@ -66,3 +69,157 @@ func TestDoWithResult(t *testing.T) {
 		}
 	}
 }
+
+func TestSendHeadersAndEmptyResponse(t *testing.T) {
+	s := testserver.New(t)
+	defer s.Stop()
+
+	// create session
+	sess := session.Must(session.New(
+		s.URL(),
+		"version",
+		credentials.New("user", "pass", "cid", "csecret"),
+	))
+	sess.HTTPClient = s.Client()
+	// login
+	s.HandlerFunc = testserver.StaticJSONHandlerFunc(t, http.StatusOK,
+		session.RequestToken{
+			InstanceURL: s.URL(),
+		})
+	assert.Nil(t, sess.Login())
+
+	tests := []struct {
+		shouldErr  bool
+		statusCode int
+		validCodes []int
+	}{
+		{false, http.StatusNoContent, []int{http.StatusNoContent}},
+		{false, http.StatusNotModified, []int{http.StatusOK, http.StatusNotModified}},
+		{true, http.StatusOK, []int{http.StatusOK, http.StatusNotModified}},
+		{true, http.StatusNotModified, []int{http.StatusOK}},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		s.HandlerFunc = testserver.ValidateRequestHandlerFunc(t, assertMsg,
+			&testserver.JSONResponseHandler{StatusCode: test.statusCode},
+			&testserver.HeaderValidator{Key: "If-Modified-Since", Value: "date"},
+			&testserver.HeaderValidator{Key: "Content-Type", Value: "text/plain"})
+
+		var got interface{}
+		req := New(sess, &Operation{
+			Method: "GET",
+			Header: http.Header{
+				"If-Modified-Since": {"date"},
+				"Content-Type":      {"text/plain"},
+			},
+		}, NewResultExpectation(JSONResult, test.validCodes...), &got,
+			func(r *http.Request) { r.Header.Set("Content-Type", "application/json") })
+
+		err := req.Send()
+		if test.shouldErr {
+			assert.NotNil(t, err, assertMsg)
+		} else {
+			assert.Nil(t, err, assertMsg)
+			assert.Nil(t, got, assertMsg)
+			assert.Equal(t, test.statusCode, req.Response().StatusCode, assertMsg)
+		}
+	}
+}
+
+func TestSendStreamResult(t *testing.T) {
+	s := testserver.New(t)
+	defer s.Stop()
+
+	// create session
+	sess := session.Must(session.New(
+		s.URL(),
+		"version",
+		credentials.New("user", "pass", "cid", "csecret"),
+	))
+	sess.HTTPClient = s.Client()
+	// login
+	s.HandlerFunc = testserver.StaticJSONHandlerFunc(t, http.StatusOK,
+		session.RequestToken{
+			InstanceURL: s.URL(),
+		})
+	assert.Nil(t, sess.Login())
+
+	tests := []struct {
+		statusCode int
+		body       string
+		result     interface{}
+		errSnippet string
+	}{
+		{http.StatusOK, "binary data", new(io.ReadCloser), ""},
+		{http.StatusNotFound, `[{"errorCode":"NOT_FOUND","message":"not found"}]`, new(io.ReadCloser), "NOT_FOUND"},
+		{http.StatusNotFound, "not found", new(io.ReadCloser), "unexpected status code"},
+		{http.StatusOK, "binary data", new(string), "stream result must be a *io.ReadCloser"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		test := test
+		s.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.statusCode)
+			_, _ = w.Write([]byte(test.body))
+		}
+
+		req := New(sess, &Operation{Method: "GET"},
+			NewResultExpectation(StreamResult, http.StatusOK), test.result)
+		err := req.Send()
+		if test.errSnippet != "" {
+			if assert.Error(t, err, assertMsg) {
+				assert.Contains(t, err.Error(), test.errSnippet, assertMsg)
+			}
+			continue
+		}
+		if !assert.Nil(t, err, assertMsg) {
+			continue
+		}
+		body := *test.result.(*io.ReadCloser)
+		b, err := ioutil.ReadAll(body)
+		assert.Nil(t, err, assertMsg)
+		assert.Nil(t, body.Close(), assertMsg)
+		assert.Equal(t, test.body, string(b), assertMsg)
+	}
+}
+
+func TestSendRetryWithoutGetBody(t *testing.T) {
+	s := testserver.New(t)
+	defer s.Stop()
+
+	// create session
+	sess := session.Must(session.New(
+		s.URL(),
+		"version",
+		credentials.New("user", "pass", "cid", "csecret"),
+	))
+	sess.HTTPClient = s.Client()
+	// every request is unauthorized, except login
+	s.HandlerFunc = func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/services/oauth2/token" {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"instance_url":"` + s.URL() + `"}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+	assert.Nil(t, sess.Login())
+
+	// request without body is retried
+	req := New(sess, &Operation{Method: "GET"}, NewResultExpectation(JSONResult, http.StatusOK), nil)
+	if err := req.Send(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unexpected status code")
+	}
+	assert.Equal(t, 4, s.RequestCount, "expected 4 requests (login, get, login, retry)")
+
+	// request with a body that cannot be reset is not retried
+	s.RequestCount = 0
+	req = New(sess, &Operation{Method: "POST", Body: ioutil.NopCloser(strings.NewReader("data"))},
+		NewResultExpectation(JSONResult, http.StatusOK), nil)
+	if err := req.Send(); assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "body cannot be reset")
+	}
+	assert.Equal(t, 2, s.RequestCount, "expected 2 requests (post, login)")
+}
@@ -1,6 +1,10 @@
 package session
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
 
 // RequestToken stores the oauth token result from the Salesforce API.
 type RequestToken struct {
@@ -12,6 +16,35 @@ type RequestToken struct {
 	Signature   string `json:"signature"`
 }
 
+// orgID returns the organization id from the identity url
+// (e.g. https://login.salesforce.com/id/<org id>/<user id>).
+func (t *RequestToken) orgID() string {
+	parts := t.identityPath()
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[1]
+}
+
+// userID returns the user id from the identity url
+// (e.g. https://login.salesforce.com/id/<org id>/<user id>).
+func (t *RequestToken) userID() string {
+	parts := t.identityPath()
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[2]
+}
+
+// identityPath returns the path segments of the identity url.
+func (t *RequestToken) identityPath() []string {
+	u, err := url.Parse(t.ID)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.Trim(u.Path, "/"), "/")
+}
+
 // LoginError is an unsuccessful login response
 type LoginError struct {
 	ErrorCode string `json:"error" xml:"Body>Fault>faultcode"`
@@ -151,3 +151,23 @@ func (s *Session) InstanceURL() string {
 	}
 	return s.requestToken.InstanceURL
 }
+
+// OrgID returns the organization id from the identity url in the Login response.
+func (s *Session) OrgID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.requestToken == nil {
+		return ""
+	}
+	return s.requestToken.orgID()
+}
+
+// UserID returns the id of the logged in user from the identity url in the Login response.
+func (s *Session) UserID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.requestToken == nil {
+		return ""
+	}
+	return s.requestToken.userID()
+}
@@ -246,3 +246,45 @@ func credsAsForm(creds credentials.OAuth) url.Values {
 	form.Set("client_secret", creds.ClientSecret)
 	return form
 }
+
+func TestOrgID(t *testing.T) {
+	tests := []struct {
+		token *RequestToken
+		want  string
+	}{
+		{nil, ""},
+		{&RequestToken{}, ""},
+		{&RequestToken{ID: "id"}, ""},
+		{&RequestToken{ID: "https://login.salesforce.com/id/00Dx0000000BV7z"}, ""},
+		{&RequestToken{ID: "https://login.salesforce.com/id/00Dx0000000BV7z/005x00000012Q9P"},
+			"00Dx0000000BV7z"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		sess := Must(New("url", "1.0", credentials.New("u", "p", "ci", "cs")))
+		sess.requestToken = test.token
+		assert.Equal(t, test.want, sess.OrgID(), assertMsg)
+	}
+}
+
+func TestUserID(t *testing.T) {
+	tests := []struct {
+		token *RequestToken
+		want  string
+	}{
+		{nil, ""},
+		{&RequestToken{}, ""},
+		{&RequestToken{ID: "id"}, ""},
+		{&RequestToken{ID: "https://login.salesforce.com/id/00Dx0000000BV7z"}, ""},
+		{&RequestToken{ID: "https://login.salesforce.com/id/00Dx0000000BV7z/005x00000012Q9P"},
+			"005x00000012Q9P"},
+	}
+
+	for _, test := range tests {
+		assertMsg := fmt.Sprintf("input: %v", test)
+		sess := Must(New("url", "1.0", credentials.New("u", "p", "ci", "cs")))
+		sess.requestToken = test.token
+		assert.Equal(t, test.want, sess.UserID(), assertMsg)
+	}
+}
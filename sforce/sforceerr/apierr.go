@@ -1,6 +1,9 @@
 package sforceerr
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // APIError is an unsuccessful response from the Salesforce API.
 type APIError struct {
@@ -14,3 +17,15 @@ type APIError struct {
 func (e *APIError) Error() string {
 	return fmt.Sprintf("%+v", *e)
 }
+
+// APIErrors is a list of errors from the Salesforce API. This is used when a response
+// can contain multiple errors (e.g. a composite subrequest).
+type APIErrors []*APIError
+
+func (e APIErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}